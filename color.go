@@ -0,0 +1,61 @@
+package objectlog
+
+import (
+	"io"
+	"os"
+)
+
+const (
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+	colorDim    = "\x1b[2m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorBlue   = "\x1b[34m"
+	colorCyan   = "\x1b[36m"
+	colorGray   = "\x1b[90m"
+)
+
+// IsTerminal returns whether the writer is a file connected to a terminal
+func IsTerminal(writer io.Writer) bool {
+	fh, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := fh.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// UseColor returns whether colored output should be written to the writer: It must be a terminal and the
+// environment variable NO_COLOR must not be set (see https://no-color.org).
+func UseColor(writer io.Writer) bool {
+	return os.Getenv("NO_COLOR") == "" && IsTerminal(writer)
+}
+
+// levelColor returns the ANSI color code for a level, determined by severity
+func levelColor(level ObjectLogLevel) string {
+	switch severity := level.Severity(); {
+	case severity < OBJECT_LOG_LEVEL_DEBUG.Severity():
+		return colorGray
+	case severity < OBJECT_LOG_LEVEL_INFO.Severity():
+		return colorCyan
+	case severity < OBJECT_LOG_LEVEL_NOTICE.Severity():
+		return colorGreen
+	case severity < OBJECT_LOG_LEVEL_WARN.Severity():
+		return colorBlue
+	case severity < OBJECT_LOG_LEVEL_ERROR.Severity():
+		return colorYellow
+	case severity < OBJECT_LOG_LEVEL_PANIC.Severity():
+		return colorRed
+	}
+	return colorBold + colorRed
+}
+
+// colorize encloses the text in the color code and a reset, if the color is not empty
+func colorize(color, text string) string {
+	if color == "" || text == "" {
+		return text
+	}
+	return color + text + colorReset
+}
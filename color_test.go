@@ -0,0 +1,33 @@
+package objectlog
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestIsTerminal(t *testing.T) {
+	assert.False(t, IsTerminal(&bytes.Buffer{}))
+	fh, err := ioutil.TempFile("", "objectlog")
+	if assert.Nil(t, err) {
+		defer os.Remove(fh.Name())
+		defer fh.Close()
+		assert.False(t, IsTerminal(fh))
+		assert.False(t, UseColor(fh))
+	}
+}
+
+func TestLevelColor(t *testing.T) {
+	assert.Equal(t, colorGray, levelColor(OBJECT_LOG_LEVEL_TRACE))
+	assert.Equal(t, colorCyan, levelColor(OBJECT_LOG_LEVEL_DEBUG))
+	assert.Equal(t, colorGreen, levelColor(OBJECT_LOG_LEVEL_INFO))
+	assert.Equal(t, colorBlue, levelColor(OBJECT_LOG_LEVEL_NOTICE))
+	assert.Equal(t, colorYellow, levelColor(OBJECT_LOG_LEVEL_WARN))
+	assert.Equal(t, colorRed, levelColor(OBJECT_LOG_LEVEL_ERROR))
+	assert.Equal(t, colorBold+colorRed, levelColor(OBJECT_LOG_LEVEL_PANIC))
+	assert.Equal(t, colorBold+colorRed, levelColor(OBJECT_LOG_LEVEL_FATAL))
+	assert.Equal(t, "", colorize(colorRed, ""))
+	assert.Equal(t, "x", colorize("", "x"))
+}
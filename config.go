@@ -0,0 +1,834 @@
+package objectlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+
+	// Config describes the logging pipeline. It can be read from JSON with `FromConfig` and from YAML with
+	// `FromConfig` of the package github.com/ukautz/objectlog/yamlconfig, which keeps this package free of
+	// dependencies.
+	//	{
+	//		"level": "info",
+	//		"overrides": "database/*=debug",
+	//		"sinks": [
+	//			{"type": "stderr", "formatter": "default"},
+	//			{"type": "file", "level": "warn", "options": {"path": "/var/log/app.log"},
+	//			 "filters": [{"message": "^healthcheck", "exclude": true}]}
+	//		],
+	//		"hooks": [{"type": "args", "options": {"args": {"app": "myapp"}}}],
+	//		"redact": {"args": ["password", "*token"], "patterns": ["\\d{16}"]}
+	//	}
+	Config struct {
+
+		// Level is the global minimum level, see `SetGlobalLevel`
+		Level string `json:"level" yaml:"level"`
+
+		// Overrides are comma separated level overrides, see `ParseLevelOverrides`
+		Overrides string `json:"overrides" yaml:"overrides"`
+
+		// Sinks are the loggers, all messages are written to
+		Sinks []SinkConfig `json:"sinks" yaml:"sinks"`
+
+		// Hooks are called for each message, before it is written to the sinks
+		Hooks []HookConfig `json:"hooks" yaml:"hooks"`
+
+		// Redact removes sensitive data from messages, before it is written to the sinks
+		Redact *RedactConfig `json:"redact" yaml:"redact"`
+	}
+
+	// SinkConfig describes a single logger of the pipeline
+	SinkConfig struct {
+
+		// Type is the name of a registered sink, see `RegisterSink`. Built-in types are "stderr",
+		// "stdout", "file", "gelf", "http", "fluent", "buffer" and, if supported by the platform, "syslog"
+		// and "journald".
+		Type string `json:"type" yaml:"type"`
+
+		// Level is the minimum level of messages written to the sink
+		Level string `json:"level" yaml:"level"`
+
+		// Formatter replaces the formatter of the `ObjectLog` for this sink
+		Formatter *FormatterConfig `json:"formatter" yaml:"formatter"`
+
+		// Filters must all accept a message, to be written to the sink
+		Filters []FilterConfig `json:"filters" yaml:"filters"`
+
+		// Options are specific to the type of the sink. The "stderr", "stdout" and "file" sinks support
+		// "timestamp" (default true) and "raw", which writes the formatted message as is, without
+		// timestamp and level, as required by line formatters like "json", "logfmt" or "text".
+		Options ConfigOptions `json:"options" yaml:"options"`
+	}
+
+	// FormatterConfig describes a formatter. In JSON and YAML, it can be provided as plain string, which is
+	// the type.
+	FormatterConfig struct {
+
+		// Type is the name of a registered formatter, see `RegisterFormatter`
+		Type string `json:"type" yaml:"type"`
+
+		// Options are specific to the type of the formatter
+		Options ConfigOptions `json:"options" yaml:"options"`
+	}
+
+	// FilterConfig describes conditions, which all must match for a message to be accepted. If exclude is
+	// set, then messages matching all conditions are rejected instead.
+	FilterConfig struct {
+
+		// Levels the message must have one of
+		Levels []string `json:"levels" yaml:"levels"`
+
+		// Prefix is a glob pattern the prefix must match, see `path.Match`
+		Prefix string `json:"prefix" yaml:"prefix"`
+
+		// Message is a regular expression the message text must match
+		Message string `json:"message" yaml:"message"`
+
+		// Exclude rejects matching messages, instead of accepting them
+		Exclude bool `json:"exclude" yaml:"exclude"`
+	}
+
+	// HookConfig describes a hook
+	HookConfig struct {
+
+		// Type is the name of a registered hook, see `RegisterHook`. The built-in "args" hook adds the
+		// args in the option "args" to all messages.
+		Type string `json:"type" yaml:"type"`
+
+		// Levels restricts the hook to messages of the given levels
+		Levels []string `json:"levels" yaml:"levels"`
+
+		// Options are specific to the type of the hook
+		Options ConfigOptions `json:"options" yaml:"options"`
+	}
+
+	// RedactConfig describes which data is redacted, see `NewRedactHook`
+	RedactConfig struct {
+
+		// Args are glob patterns of arg names, whose values are redacted
+		Args []string `json:"args" yaml:"args"`
+
+		// Patterns are regular expressions, whose matches are redacted
+		Patterns []string `json:"patterns" yaml:"patterns"`
+	}
+
+	// ConfigOptions are type specific options of sinks, formatters and hooks
+	ConfigOptions map[string]interface{}
+
+	// ConfigError points to the invalid part of a configuration
+	ConfigError struct {
+		Path string
+		Err  error
+	}
+
+	// SinkFactory creates a logger from options
+	SinkFactory func(options ConfigOptions) (ObjectLogger, error)
+
+	// FormatterFactory creates a formatter from options
+	FormatterFactory func(options ConfigOptions) (ObjectLogFormatter, error)
+
+	// HookFactory creates a hook from options
+	HookFactory func(options ConfigOptions) (ObjectLogHook, error)
+)
+
+type (
+
+	// configFileStandardLogger is a *StandardLogger of the "file" sink, which closes the file
+	configFileStandardLogger struct {
+		*StandardLogger
+		file *os.File
+	}
+
+	// configFileWriterLogger is a *WriterLogger of the "file" sink, which closes the file
+	configFileWriterLogger struct {
+		*WriterLogger
+		file *os.File
+	}
+)
+
+var (
+	configMutex      sync.RWMutex
+	configSinks      = map[string]SinkFactory{}
+	configFormatters = map[string]FormatterFactory{}
+	configHooks      = map[string]HookFactory{}
+)
+
+func init() {
+	RegisterSink("stderr", func(options ConfigOptions) (ObjectLogger, error) {
+		return newConfigStandardLogger(os.Stderr, options)
+	})
+	RegisterSink("stdout", func(options ConfigOptions) (ObjectLogger, error) {
+		return newConfigStandardLogger(os.Stdout, options)
+	})
+	RegisterSink("file", func(options ConfigOptions) (ObjectLogger, error) {
+		name, err := options.String("path", "")
+		if err != nil {
+			return nil, err
+		} else if name == "" {
+			return nil, &ConfigError{"path", fmt.Errorf("required")}
+		}
+		fh, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, &ConfigError{"path", err}
+		}
+		logger, err := newConfigStandardLogger(fh, options)
+		if err != nil {
+			fh.Close()
+			return nil, err
+		} else if writer, ok := logger.(*WriterLogger); ok {
+			return &configFileWriterLogger{writer, fh}, nil
+		}
+		return &configFileStandardLogger{logger.(*StandardLogger), fh}, nil
+	})
+	RegisterSink("gelf", func(options ConfigOptions) (ObjectLogger, error) {
+		network, err := options.String("network", "udp")
+		if err != nil {
+			return nil, err
+		}
+		address, err := options.String("address", "")
+		if err != nil {
+			return nil, err
+		} else if address == "" {
+			return nil, &ConfigError{"address", fmt.Errorf("required")}
+		}
+		gelfOptions := GelfOptions{}
+		if gelfOptions.Host, err = options.String("host", ""); err != nil {
+			return nil, err
+		}
+		compression, err := options.String("compression", "")
+		if err != nil {
+			return nil, err
+		}
+		gelfOptions.Compression = GelfCompression(compression)
+		if gelfOptions.ChunkSize, err = options.Int("chunk_size", 0); err != nil {
+			return nil, err
+		}
+		if gelfOptions.Timeout, err = options.Duration("timeout", 0); err != nil {
+			return nil, err
+		}
+		logger, err := NewGelfLogger(network, address, gelfOptions)
+		if err != nil {
+			return nil, &ConfigError{"", err}
+		}
+		return logger, nil
+	})
+	RegisterSink("fluent", func(options ConfigOptions) (ObjectLogger, error) {
+		network, err := options.String("network", "tcp")
+		if err != nil {
+			return nil, err
+		}
+		address, err := options.String("address", "localhost:24224")
+		if err != nil {
+			return nil, err
+		}
+		fluentOptions := FluentOptions{}
+		if fluentOptions.Tag, err = options.String("tag", ""); err != nil {
+			return nil, err
+		}
+		if fluentOptions.BatchSize, err = options.Int("batch_size", 0); err != nil {
+			return nil, err
+		}
+		if fluentOptions.Interval, err = options.Duration("interval", 0); err != nil {
+			return nil, err
+		}
+		if fluentOptions.RequireAck, err = options.Bool("require_ack", false); err != nil {
+			return nil, err
+		}
+		if fluentOptions.Timeout, err = options.Duration("timeout", 0); err != nil {
+			return nil, err
+		}
+		logger, err := NewFluentLogger(network, address, fluentOptions)
+		if err != nil {
+			return nil, &ConfigError{"", err}
+		}
+		return logger, nil
+	})
+	RegisterSink("http", func(options ConfigOptions) (ObjectLogger, error) {
+		url, err := options.String("url", "")
+		if err != nil {
+			return nil, err
+		} else if url == "" {
+			return nil, &ConfigError{"url", fmt.Errorf("required")}
+		}
+		httpOptions := HTTPOptions{}
+		format, err := options.String("format", "")
+		if err != nil {
+			return nil, err
+		}
+		httpOptions.Format = HTTPFormat(format)
+		if err := checkHTTPFormat(httpOptions.Format); err != nil {
+			return nil, &ConfigError{"format", err}
+		}
+		if httpOptions.BatchSize, err = options.Int("batch_size", 0); err != nil {
+			return nil, err
+		}
+		if httpOptions.Interval, err = options.Duration("interval", 0); err != nil {
+			return nil, err
+		}
+		if httpOptions.Gzip, err = options.Bool("gzip", false); err != nil {
+			return nil, err
+		}
+		if httpOptions.Retries, err = options.Int("retries", 0); err != nil {
+			return nil, err
+		}
+		if httpOptions.MaxInFlight, err = options.Int("max_in_flight", 0); err != nil {
+			return nil, err
+		}
+		if httpOptions.Index, err = options.String("index", ""); err != nil {
+			return nil, err
+		}
+		labels, err := options.Map("labels")
+		if err != nil {
+			return nil, err
+		}
+		httpOptions.Labels = map[string]string{}
+		for key, value := range labels {
+			httpOptions.Labels[key] = fmt.Sprint(value)
+		}
+		logger, err := NewHTTPLogger(url, httpOptions)
+		if err != nil {
+			return nil, err
+		}
+		return logger, nil
+	})
+	RegisterSink("buffer", func(options ConfigOptions) (ObjectLogger, error) {
+		return NewBufferObjectLog(), nil
+	})
+	RegisterFormatter("default", func(options ConfigOptions) (ObjectLogFormatter, error) {
+		return DefaultFormatter, nil
+	})
+	RegisterFormatter("text", func(options ConfigOptions) (ObjectLogFormatter, error) {
+		color, err := options.Bool("color", false)
+		if err != nil {
+			return nil, err
+		}
+		return NewTextFormatter(color), nil
+	})
+	RegisterFormatter("console", func(options ConfigOptions) (ObjectLogFormatter, error) {
+		color, err := options.Bool("color", false)
+		if err != nil {
+			return nil, err
+		}
+		relative, err := options.Bool("relative", false)
+		if err != nil {
+			return nil, err
+		}
+		timeFormat, err := options.String("time_format", "")
+		if err != nil {
+			return nil, err
+		}
+		return NewConsoleFormatter(ConsoleOptions{
+			Color:      color,
+			Relative:   relative,
+			TimeFormat: timeFormat,
+		}), nil
+	})
+	RegisterFormatter("template", func(options ConfigOptions) (ObjectLogFormatter, error) {
+		text, err := options.String("template", "")
+		if err != nil {
+			return nil, err
+		} else if text == "" {
+			return nil, &ConfigError{"template", fmt.Errorf("required")}
+		}
+		formatter, err := NewTemplateFormatter(text)
+		if err != nil {
+			return nil, &ConfigError{"template", err}
+		}
+		return formatter, nil
+	})
+	RegisterFormatter("gelf", func(options ConfigOptions) (ObjectLogFormatter, error) {
+		host, err := options.String("host", "")
+		if err != nil {
+			return nil, err
+		}
+		return NewGelfFormatter(host), nil
+	})
+	RegisterFormatter("json", func(options ConfigOptions) (ObjectLogFormatter, error) {
+		return JSONFormatter, nil
+	})
+	RegisterFormatter("logfmt", func(options ConfigOptions) (ObjectLogFormatter, error) {
+		return LogfmtFormatter, nil
+	})
+	RegisterHook("args", func(options ConfigOptions) (ObjectLogHook, error) {
+		args, err := options.Map("args")
+		if err != nil {
+			return nil, err
+		}
+		return NewArgsHook(args), nil
+	})
+}
+
+// RegisterSink makes a sink type available in the configuration. Existing types are replaced.
+//	objectlog.RegisterSink("mysink", func(options objectlog.ConfigOptions) (objectlog.ObjectLogger, error) {
+//		address, err := options.String("address", "localhost:1234")
+//		if err != nil {
+//			return nil, err
+//		}
+//		return NewMySink(address), nil
+//	})
+func RegisterSink(typ string, factory SinkFactory) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	configSinks[typ] = factory
+}
+
+// RegisterFormatter makes a formatter type available in the configuration. Existing types are replaced.
+func RegisterFormatter(typ string, factory FormatterFactory) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	configFormatters[typ] = factory
+}
+
+// RegisterHook makes a hook type available in the configuration. Existing types are replaced.
+func RegisterHook(typ string, factory HookFactory) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	configHooks[typ] = factory
+}
+
+// FromConfig reads the configuration as JSON, see `ParseConfig`, builds the logging pipeline and applies it, see
+// `Config.Apply`.
+//	fh, _ := os.Open("logging.json")
+//	logger, err := objectlog.FromConfig(fh)
+func FromConfig(reader io.Reader) (ObjectLogger, error) {
+	config, err := ParseConfig(reader)
+	if err != nil {
+		return nil, err
+	}
+	return config.Apply()
+}
+
+// ParseConfig reads the configuration as JSON. Unknown fields are rejected. YAML is read by the package
+// github.com/ukautz/objectlog/yamlconfig.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	config := &Config{}
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return nil, &ConfigError{"", err}
+	}
+	return config, nil
+}
+
+// Apply builds the logging pipeline, sets it as `DefaultLogger` and sets the global level and level
+// overrides, if configured.
+func (this *Config) Apply() (ObjectLogger, error) {
+	logger, err := this.Build()
+	if err != nil {
+		return nil, err
+	}
+	level, _ := parseConfigLevel("level", this.Level)
+	overrides, _ := ParseLevelOverrides(this.Overrides)
+	if level != "" {
+		SetGlobalLevel(level)
+	}
+	if len(overrides) > 0 {
+		SetLevelOverrides(overrides)
+	}
+	SetDefaultLogger(logger)
+	return logger, nil
+}
+
+// Build validates the configuration and builds the logging pipeline, without applying it. If the
+// configuration is invalid, then the sinks, which have been built already, are closed.
+func (this *Config) Build() (ObjectLogger, error) {
+	if _, err := parseConfigLevel("level", this.Level); err != nil {
+		return nil, err
+	}
+	if _, err := ParseLevelOverrides(this.Overrides); err != nil {
+		return nil, &ConfigError{"overrides", err}
+	}
+	if len(this.Sinks) == 0 {
+		return nil, &ConfigError{"sinks", fmt.Errorf("at least one sink required")}
+	}
+
+	// hooks are built first, as they do not open files or connections like sinks
+	hooks := []ObjectLogHook{}
+	for i, config := range this.Hooks {
+		hook, err := config.build()
+		if err != nil {
+			return nil, prefixConfigError(fmt.Sprintf("hooks[%d]", i), err)
+		}
+		hooks = append(hooks, hook)
+	}
+	if this.Redact != nil {
+		hook, err := this.Redact.build()
+		if err != nil {
+			return nil, prefixConfigError("redact", err)
+		}
+		hooks = append(hooks, hook)
+	}
+
+	multi := NewMultiLogger()
+	opened := []ObjectLogger{}
+	for i, sink := range this.Sinks {
+		logger, base, err := sink.build()
+		if err != nil {
+			closeConfigLoggers(opened)
+			return nil, prefixConfigError(fmt.Sprintf("sinks[%d]", i), err)
+		}
+		opened = append(opened, base)
+		multi.AddLogger(logger)
+	}
+	if len(hooks) == 0 {
+		return multi, nil
+	}
+	return NewHookLogger(multi, hooks...), nil
+}
+
+// build returns the logger of the sink, including formatter and filters, and the logger created by the
+// factory. The factory is called last, so that nothing is opened if the rest of the config is invalid.
+func (this *SinkConfig) build() (logger, base ObjectLogger, err error) {
+	configMutex.RLock()
+	factory, ok := configSinks[this.Type]
+	configMutex.RUnlock()
+	if !ok {
+		return nil, nil, &ConfigError{"type", fmt.Errorf("unknown sink type %q, registered are %s", this.Type, registeredNames(configSinks))}
+	}
+	level, err := parseConfigLevel("level", this.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+	var formatter ObjectLogFormatter
+	if this.Formatter != nil {
+		if formatter, err = this.Formatter.build(); err != nil {
+			return nil, nil, prefixConfigError("formatter", err)
+		}
+	}
+	filters := []ObjectLogFilter{}
+	if level != "" {
+		filters = append(filters, LevelFilter(level))
+	}
+	for i, config := range this.Filters {
+		filter, err := config.build()
+		if err != nil {
+			return nil, nil, prefixConfigError(fmt.Sprintf("filters[%d]", i), err)
+		}
+		filters = append(filters, filter)
+	}
+
+	if base, err = factory(this.Options); err != nil {
+		return nil, nil, prefixConfigError("options", err)
+	}
+	logger = base
+	if formatter != nil {
+		logger = NewFormatterLogger(logger, formatter)
+	}
+	if len(filters) > 0 {
+		logger = NewFilterLogger(logger, func(entry *ObjectLogEntry) bool {
+			for _, filter := range filters {
+				if !filter(entry) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+	return logger, base, nil
+}
+
+// UnmarshalJSON accepts the formatter config as object or as plain string, which is the type
+func (this *FormatterConfig) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		this.Options = nil
+		return json.Unmarshal(data, &this.Type)
+	}
+	type plain FormatterConfig
+	return json.Unmarshal(data, (*plain)(this))
+}
+
+// UnmarshalYAML accepts the formatter config as mapping or as plain string, which is the type. It implements
+// the `yaml.Unmarshaler` interface of gopkg.in/yaml.v2, which is supported by v3 as well.
+func (this *FormatterConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var typ string
+	if err := unmarshal(&typ); err == nil {
+		this.Type, this.Options = typ, nil
+		return nil
+	}
+	type plain FormatterConfig
+	return unmarshal((*plain)(this))
+}
+
+func (this *FormatterConfig) build() (ObjectLogFormatter, error) {
+	configMutex.RLock()
+	factory, ok := configFormatters[this.Type]
+	configMutex.RUnlock()
+	if !ok {
+		return nil, &ConfigError{"type", fmt.Errorf("unknown formatter type %q, registered are %s", this.Type, registeredNames(configFormatters))}
+	}
+	formatter, err := factory(this.Options)
+	if err != nil {
+		return nil, prefixConfigError("options", err)
+	}
+	return formatter, nil
+}
+
+func (this *FilterConfig) build() (ObjectLogFilter, error) {
+	levels := map[ObjectLogLevel]bool{}
+	for i, name := range this.Levels {
+		level, err := parseConfigLevel(fmt.Sprintf("levels[%d]", i), name)
+		if err != nil {
+			return nil, err
+		}
+		levels[level] = true
+	}
+	if _, err := path.Match(this.Prefix, ""); err != nil {
+		return nil, &ConfigError{"prefix", err}
+	}
+	var message *regexp.Regexp
+	if this.Message != "" {
+		var err error
+		if message, err = regexp.Compile(this.Message); err != nil {
+			return nil, &ConfigError{"message", err}
+		}
+	}
+	prefix, exclude := this.Prefix, this.Exclude
+	return func(entry *ObjectLogEntry) bool {
+		match := len(levels) == 0 || levels[entry.Level]
+		if match && prefix != "" {
+			match, _ = path.Match(prefix, entry.Prefix)
+		}
+		if match && message != nil {
+			match = message.MatchString(entry.Text())
+		}
+		return match != exclude
+	}, nil
+}
+
+func (this *HookConfig) build() (ObjectLogHook, error) {
+	configMutex.RLock()
+	factory, ok := configHooks[this.Type]
+	configMutex.RUnlock()
+	if !ok {
+		return nil, &ConfigError{"type", fmt.Errorf("unknown hook type %q, registered are %s", this.Type, registeredNames(configHooks))}
+	}
+	levels := map[ObjectLogLevel]bool{}
+	for i, name := range this.Levels {
+		level, err := parseConfigLevel(fmt.Sprintf("levels[%d]", i), name)
+		if err != nil {
+			return nil, err
+		}
+		levels[level] = true
+	}
+	hook, err := factory(this.Options)
+	if err != nil {
+		return nil, prefixConfigError("options", err)
+	}
+	if len(levels) == 0 {
+		return hook, nil
+	}
+	return func(entry *ObjectLogEntry) {
+		if levels[entry.Level] {
+			hook(entry)
+		}
+	}, nil
+}
+
+func (this *RedactConfig) build() (ObjectLogHook, error) {
+	for i, pattern := range this.Args {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, &ConfigError{fmt.Sprintf("args[%d]", i), err}
+		}
+	}
+	patterns := make([]*regexp.Regexp, len(this.Patterns))
+	for i, pattern := range this.Patterns {
+		var err error
+		if patterns[i], err = regexp.Compile(pattern); err != nil {
+			return nil, &ConfigError{fmt.Sprintf("patterns[%d]", i), err}
+		}
+	}
+	return NewRedactHook(this.Args, patterns), nil
+}
+
+// Error implements `error`
+func (this *ConfigError) Error() string {
+	if this.Path == "" {
+		return "objectlog: invalid config: " + this.Err.Error()
+	}
+	return "objectlog: invalid config at " + this.Path + ": " + this.Err.Error()
+}
+
+// prefixConfigError prepends the path to the path of config errors, or wraps other errors
+func prefixConfigError(path string, err error) error {
+	if cerr, ok := err.(*ConfigError); ok {
+		if cerr.Path != "" {
+			path += "." + cerr.Path
+		}
+		return &ConfigError{path, cerr.Err}
+	}
+	return &ConfigError{path, err}
+}
+
+func parseConfigLevel(path, name string) (ObjectLogLevel, error) {
+	if name == "" {
+		return "", nil
+	}
+	level, err := ParseLevel(name)
+	if err != nil {
+		return "", &ConfigError{path, err}
+	}
+	return level, nil
+}
+
+func registeredNames(registry interface{}) string {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+	names := []string{}
+	switch r := registry.(type) {
+	case map[string]SinkFactory:
+		for name := range r {
+			names = append(names, name)
+		}
+	case map[string]FormatterFactory:
+		for name := range r {
+			names = append(names, name)
+		}
+	case map[string]HookFactory:
+		for name := range r {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// closeConfigLoggers closes all loggers, which implement `io.Closer`
+func closeConfigLoggers(loggers []ObjectLogger) {
+	for _, logger := range loggers {
+		if closer, ok := logger.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
+
+func newConfigStandardLogger(writer io.Writer, options ConfigOptions) (ObjectLogger, error) {
+	raw, err := options.Bool("raw", false)
+	if err != nil {
+		return nil, err
+	} else if raw {
+		return NewWriterLogger(writer, nil), nil
+	}
+	timestamp, err := options.Bool("timestamp", true)
+	if err != nil {
+		return nil, err
+	}
+	flags := 0
+	if timestamp {
+		flags = log.LstdFlags
+	}
+	return NewStandardLogger(log.New(writer, "", flags)), nil
+}
+
+// Close closes the file
+func (this *configFileStandardLogger) Close() error {
+	return this.file.Close()
+}
+
+// Close closes the file
+func (this *configFileWriterLogger) Close() error {
+	return this.file.Close()
+}
+
+/*
+------------------------------------
+  OPTIONS
+------------------------------------
+*/
+
+// String returns the string option, or the default if not set
+func (this ConfigOptions) String(key, def string) (string, error) {
+	value, ok := this[key]
+	if !ok || value == nil {
+		return def, nil
+	}
+	if str, ok := value.(string); ok {
+		return str, nil
+	}
+	return "", &ConfigError{key, fmt.Errorf("expected string, got %T", value)}
+}
+
+// Bool returns the boolean option, or the default if not set
+func (this ConfigOptions) Bool(key string, def bool) (bool, error) {
+	value, ok := this[key]
+	if !ok || value == nil {
+		return def, nil
+	}
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, nil
+		}
+	}
+	return false, &ConfigError{key, fmt.Errorf("expected boolean, got %v", value)}
+}
+
+// Int returns the integer option, or the default if not set
+func (this ConfigOptions) Int(key string, def int) (int, error) {
+	value, ok := this[key]
+	if !ok || value == nil {
+		return def, nil
+	}
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i, nil
+		}
+	}
+	return 0, &ConfigError{key, fmt.Errorf("expected integer, got %v", value)}
+}
+
+// Duration returns the duration option, or the default if not set. Durations are strings like "1m30s".
+func (this ConfigOptions) Duration(key string, def time.Duration) (time.Duration, error) {
+	str, err := this.String(key, "")
+	if err != nil || str == "" {
+		return def, err
+	}
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, &ConfigError{key, err}
+	}
+	return duration, nil
+}
+
+// Map returns the map option, or an empty map if not set
+func (this ConfigOptions) Map(key string) (map[string]interface{}, error) {
+	value, ok := this[key]
+	if !ok || value == nil {
+		return map[string]interface{}{}, nil
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case ConfigOptions:
+		return v, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = val
+		}
+		return m, nil
+	}
+	return nil, &ConfigError{key, fmt.Errorf("expected map, got %T", value)}
+}
@@ -0,0 +1,35 @@
+//go:build linux
+// +build linux
+
+package objectlog
+
+import (
+	"fmt"
+)
+
+// init registers the "journald" sink with the options "socket", "identifier" and "fields"
+func init() {
+	RegisterSink("journald", func(options ConfigOptions) (ObjectLogger, error) {
+		journaldOptions := JournaldOptions{}
+		var err error
+		if journaldOptions.Socket, err = options.String("socket", ""); err != nil {
+			return nil, err
+		}
+		if journaldOptions.Identifier, err = options.String("identifier", ""); err != nil {
+			return nil, err
+		}
+		fields, err := options.Map("fields")
+		if err != nil {
+			return nil, err
+		}
+		journaldOptions.Fields = map[string]string{}
+		for name, value := range fields {
+			journaldOptions.Fields[JournaldFieldName(name)] = fmt.Sprint(value)
+		}
+		logger, err := NewJournaldLogger(journaldOptions)
+		if err != nil {
+			return nil, &ConfigError{"socket", err}
+		}
+		return logger, nil
+	})
+}
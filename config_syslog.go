@@ -0,0 +1,67 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package objectlog
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+)
+
+var (
+	syslogFacilities = map[string]syslog.Priority{
+		"kern":     syslog.LOG_KERN,
+		"user":     syslog.LOG_USER,
+		"mail":     syslog.LOG_MAIL,
+		"daemon":   syslog.LOG_DAEMON,
+		"auth":     syslog.LOG_AUTH,
+		"syslog":   syslog.LOG_SYSLOG,
+		"lpr":      syslog.LOG_LPR,
+		"news":     syslog.LOG_NEWS,
+		"uucp":     syslog.LOG_UUCP,
+		"cron":     syslog.LOG_CRON,
+		"authpriv": syslog.LOG_AUTHPRIV,
+		"ftp":      syslog.LOG_FTP,
+		"local0":   syslog.LOG_LOCAL0,
+		"local1":   syslog.LOG_LOCAL1,
+		"local2":   syslog.LOG_LOCAL2,
+		"local3":   syslog.LOG_LOCAL3,
+		"local4":   syslog.LOG_LOCAL4,
+		"local5":   syslog.LOG_LOCAL5,
+		"local6":   syslog.LOG_LOCAL6,
+		"local7":   syslog.LOG_LOCAL7,
+	}
+)
+
+// init registers the "syslog" sink with the options "network" and "address" (defaulting to the local
+// syslog), "tag" and "facility" (defaults to "user")
+func init() {
+	RegisterSink("syslog", func(options ConfigOptions) (ObjectLogger, error) {
+		network, err := options.String("network", "")
+		if err != nil {
+			return nil, err
+		}
+		address, err := options.String("address", "")
+		if err != nil {
+			return nil, err
+		}
+		tag, err := options.String("tag", "")
+		if err != nil {
+			return nil, err
+		}
+		name, err := options.String("facility", "user")
+		if err != nil {
+			return nil, err
+		}
+		facility, ok := syslogFacilities[strings.ToLower(name)]
+		if !ok {
+			return nil, &ConfigError{"facility", fmt.Errorf("unknown facility %q", name)}
+		}
+		writer, err := syslog.Dial(network, address, facility|syslog.LOG_INFO, tag)
+		if err != nil {
+			return nil, &ConfigError{"address", err}
+		}
+		return NewSyslogLogger(writer), nil
+	})
+}
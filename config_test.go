@@ -0,0 +1,223 @@
+package objectlog
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	testConfigBuffers = map[string]*BufferObjectLogger{}
+	testConfigClosers = []*testConfigCloser{}
+)
+
+// testConfigCloser records whether it was closed
+type testConfigCloser struct {
+	*BufferObjectLogger
+	closed bool
+}
+
+func (this *testConfigCloser) Close() error {
+	this.closed = true
+	return nil
+}
+
+func init() {
+	RegisterSink("test-buffer", func(options ConfigOptions) (ObjectLogger, error) {
+		name, err := options.String("name", "")
+		if err != nil {
+			return nil, err
+		}
+		buf := NewBufferObjectLog()
+		testConfigBuffers[name] = buf
+		return buf, nil
+	})
+	RegisterSink("test-closer", func(options ConfigOptions) (ObjectLogger, error) {
+		closer := &testConfigCloser{BufferObjectLogger: NewBufferObjectLog()}
+		testConfigClosers = append(testConfigClosers, closer)
+		return closer, nil
+	})
+}
+
+func TestFromConfig(t *testing.T) {
+	defer resetRuntimeLevels()
+	defer func(logger ObjectLogger) { DefaultLogger = logger }(DefaultLogger)
+
+	dir, err := ioutil.TempDir("", "objectlog")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "app.log")
+
+	logger, err := FromConfig(strings.NewReader(`{
+		"level": "debug",
+		"overrides": "noisy=error",
+		"sinks": [
+			{"type": "test-buffer", "options": {"name": "all"}},
+			{"type": "test-buffer", "level": "warn", "formatter": "default", "options": {"name": "warn"}},
+			{"type": "test-buffer", "options": {"name": "filtered"}, "filters": [
+				{"message": "^health", "exclude": true},
+				{"levels": ["info", "error"]}
+			]},
+			{"type": "file", "options": {"path": "` + filepath.ToSlash(file) + `", "timestamp": false}}
+		],
+		"hooks": [{"type": "args", "levels": ["error"], "options": {"args": {"app": "test"}}}],
+		"redact": {"args": ["password"]}
+	}`))
+	assert.Nil(t, err)
+	assert.Equal(t, logger, DefaultLogger)
+	assert.Equal(t, OBJECT_LOG_LEVEL_DEBUG, GlobalLevel())
+
+	ol := NewObjectLog().SetLogArg("password", "secret")
+	ol.LogTrace("hidden")
+	ol.LogDebug("debug")
+	ol.LogInfo("healthcheck")
+	ol.LogInfo("info")
+	ol.LogWarn("warn")
+	ol.LogError("error")
+	NewObjectLog().SetLogName("noisy").LogWarn("hidden")
+
+	assert.Equal(t, strings.Join([]string{
+		`[DBG] debug :: {"password":"***"}`,
+		`[INF] healthcheck :: {"password":"***"}`,
+		`[INF] info :: {"password":"***"}`,
+		`[WRN] warn :: {"password":"***"}`,
+		`[ERR] error :: {"app":"test","password":"***"}`,
+	}, "\n")+"\n", testConfigBuffers["all"].Buffer().String())
+	assert.Equal(t, strings.Join([]string{
+		`[WRN] warn :: {"password":"***"}`,
+		`[ERR] error :: {"app":"test","password":"***"}`,
+	}, "\n")+"\n", testConfigBuffers["warn"].Buffer().String())
+	assert.Equal(t, strings.Join([]string{
+		`[INF] info :: {"password":"***"}`,
+		`[ERR] error :: {"app":"test","password":"***"}`,
+	}, "\n")+"\n", testConfigBuffers["filtered"].Buffer().String())
+	raw, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Contains(t, string(raw), `[WARN] warn :: {"password":"***"}`)
+}
+
+func TestFromConfig_Raw(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectlog")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "app.log")
+
+	config, err := ParseConfig(strings.NewReader(`{"sinks": [
+		{"type": "file", "formatter": "logfmt", "options": {"path": "` + filepath.ToSlash(file) + `", "raw": true}}
+	]}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	logger, err := config.Build()
+	if !assert.Nil(t, err) {
+		return
+	}
+	NewObjectLog(logger).SetLogArg("foo", "bar").LogWarn("warn")
+	raw, err := ioutil.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Regexp(t, `^time=\S+ level=warn msg=warn foo=bar\n$`, string(raw))
+}
+
+func TestFromConfig_Errors(t *testing.T) {
+	for config, expect := range map[string]string{
+		`{"sinks": [{"type": "stderr"}], "foo": 1}`: `objectlog: invalid config: json: unknown field "foo"`,
+		`{"sinks": []}`: `objectlog: invalid config at sinks: at least one sink required`,
+		`{"level": "nope", "sinks": [{"type": "stderr"}]}`:                                                           `objectlog: invalid config at level: objectlog: unknown level "nope"`,
+		`{"overrides": "nope", "sinks": [{"type": "stderr"}]}`:                                                       `objectlog: invalid config at overrides: objectlog: invalid level override "nope", expected <pattern>=<level>`,
+		`{"sinks": [{"type": "stderr"}, {"type": "nope"}]}`:                                                          `objectlog: invalid config at sinks[1].type: unknown sink type "nope", registered are `,
+		`{"sinks": [{"type": "stderr", "level": "nope"}]}`:                                                           `objectlog: invalid config at sinks[0].level: objectlog: unknown level "nope"`,
+		`{"sinks": [{"type": "file"}]}`:                                                                              `objectlog: invalid config at sinks[0].options.path: required`,
+		`{"sinks": [{"type": "stderr", "options": {"timestamp": 1}}]}`:                                               `objectlog: invalid config at sinks[0].options.timestamp: expected boolean, got 1`,
+		`{"sinks": [{"type": "stderr", "formatter": {"type": "nope"}}]}`:                                             `objectlog: invalid config at sinks[0].formatter.type: unknown formatter type "nope", registered are `,
+		`{"sinks": [{"type": "stderr", "formatter": {"type": "template"}}]}`:                                         `objectlog: invalid config at sinks[0].formatter.options.template: required`,
+		`{"sinks": [{"type": "stderr", "formatter": {"type": "template", "options": {"template": "{{ .Nope }}"}}}]}`: `objectlog: invalid config at sinks[0].formatter.options.template: template: objectlog:1:3: executing`,
+		`{"sinks": [{"type": "http", "options": {"url": "http://localhost", "format": "xml"}}]}`:                     `objectlog: invalid config at sinks[0].options.format: objectlog: unsupported HTTP format "xml"`,
+		`{"sinks": [{"type": "stderr", "filters": [{}, {"message": "("}]}]}`:                                         `objectlog: invalid config at sinks[0].filters[1].message: error parsing regexp: missing closing ): ` + "`(`",
+		`{"sinks": [{"type": "stderr", "filters": [{"levels": ["x"]}]}]}`:                                            `objectlog: invalid config at sinks[0].filters[0].levels[0]: objectlog: unknown level "x"`,
+		`{"sinks": [{"type": "stderr"}], "hooks": [{"type": "nope"}]}`:                                               `objectlog: invalid config at hooks[0].type: unknown hook type "nope", registered are `,
+		`{"sinks": [{"type": "stderr"}], "hooks": [{"type": "args", "options": {"args": 1}}]}`:                       `objectlog: invalid config at hooks[0].options.args: expected map, got float64`,
+		`{"sinks": [{"type": "stderr"}], "redact": {"patterns": ["("]}}`:                                             `objectlog: invalid config at redact.patterns[0]: error parsing regexp: missing closing ): ` + "`(`",
+	} {
+		_, err := FromConfig(strings.NewReader(config))
+		if assert.NotNil(t, err, config) {
+			assert.True(t, strings.HasPrefix(err.Error(), expect), err.Error())
+		}
+	}
+}
+
+func TestFromConfig_CloseOnError(t *testing.T) {
+	testConfigClosers = nil
+	_, err := FromConfig(strings.NewReader(`{"sinks": [
+		{"type": "test-closer"},
+		{"type": "test-closer", "formatter": "nope"}
+	]}`))
+	assert.NotNil(t, err)
+	if assert.Len(t, testConfigClosers, 1, "sink with invalid formatter is not created") {
+		assert.True(t, testConfigClosers[0].closed)
+	}
+
+	testConfigClosers = nil
+	_, err = FromConfig(strings.NewReader(`{"sinks": [{"type": "test-closer"}], "hooks": [{"type": "nope"}]}`))
+	assert.NotNil(t, err)
+	assert.Empty(t, testConfigClosers, "sinks are not created with invalid hooks")
+}
+
+func TestFromConfig_FileClose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectlog")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	for _, raw := range []bool{false, true} {
+		logger, err := configSinks["file"](ConfigOptions{"path": filepath.Join(dir, "app.log"), "raw": raw})
+		if !assert.Nil(t, err) {
+			continue
+		}
+		closer, ok := logger.(io.Closer)
+		if assert.True(t, ok) {
+			assert.Nil(t, closer.Close())
+			assert.NotNil(t, closer.Close(), "already closed")
+		}
+	}
+}
+
+func TestFormatterConfig_UnmarshalYAML(t *testing.T) {
+	// gopkg.in/yaml.v2 passes a function decoding the node into the given value
+	unmarshal := func(raw string) func(interface{}) error {
+		return func(value interface{}) error {
+			return json.Unmarshal([]byte(raw), value)
+		}
+	}
+	config := &FormatterConfig{}
+	assert.Nil(t, config.UnmarshalYAML(unmarshal(`"json"`)))
+	assert.Equal(t, &FormatterConfig{Type: "json"}, config)
+	assert.Nil(t, config.UnmarshalYAML(unmarshal(`{"type": "text", "options": {"color": true}}`)))
+	assert.Equal(t, &FormatterConfig{Type: "text", Options: ConfigOptions{"color": true}}, config)
+}
+
+func TestConfigOptions(t *testing.T) {
+	options := ConfigOptions{"str": "foo", "int": float64(3), "bool": "true", "duration": "1m", "map": map[interface{}]interface{}{"a": 1}}
+	str, err := options.String("str", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", str)
+	str, err = options.String("missing", "default")
+	assert.Nil(t, err)
+	assert.Equal(t, "default", str)
+	i, err := options.Int("int", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, i)
+	_, err = options.Int("str", 0)
+	assert.EqualError(t, err, "objectlog: invalid config at str: expected integer, got foo")
+	b, err := options.Bool("bool", false)
+	assert.Nil(t, err)
+	assert.True(t, b)
+	d, err := options.Duration("duration", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "1m0s", d.String())
+	m, err := options.Map("map")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": 1}, m)
+}
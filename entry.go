@@ -0,0 +1,187 @@
+package objectlog
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+type (
+
+	// ObjectLogEntry is the structured representation of a single log message. It is passed to loggers,
+	// which implement `EntryLogger`, all others receive the entry formatted as string.
+	ObjectLogEntry struct {
+
+		// Level of the message
+		Level ObjectLogLevel
+
+		// Time the message was written
+		Time time.Time
+
+		// Prefix of the `ObjectLog`
+		Prefix string
+
+		// Suffix of the `ObjectLog`
+		Suffix string
+
+		// Message is the unformatted message, see `Text`
+		Message string
+
+		// MessageArgs are the arguments for the message
+		MessageArgs []interface{}
+
+		// Args are the evaluated log args of the `ObjectLog`. They are shared with the object and other
+		// entries and must not be modified, see `Copy`.
+		Args map[string]interface{}
+
+		// Fields are the evaluated fields of the message, which overlay the args, see `LogArgs`
+		Fields map[string]interface{}
+
+		// Formatter of the `ObjectLog`, which is used by `String` (can be nil)
+		Formatter ObjectLogFormatter
+
+		// callers of the message, which are rendered by formatters of `NewTemplateFormatter`
+		callers []uintptr
+	}
+
+	// EntryLogger can be implemented by an `ObjectLogger` to receive the structured entry, instead of the
+	// formatted message
+	EntryLogger interface {
+		// LogEntry writes the entry
+		LogEntry(entry *ObjectLogEntry)
+	}
+
+	// EntryWriter can be implemented by an `ObjectLogger` to report whether an entry was written
+	// successfully, e.g. to retry writing later
+	EntryWriter interface {
+		// WriteEntry writes the entry and returns an error, if it could not be written
+		WriteEntry(entry *ObjectLogEntry) error
+	}
+)
+
+// NewTextEntry creates new *ObjectLogEntry from an already formatted message. Loggers which implement
+// `EntryLogger` use it to handle messages, which are written with the level methods of `ObjectLogger`.
+func NewTextEntry(level ObjectLogLevel, msg string) *ObjectLogEntry {
+	return &ObjectLogEntry{
+		Level:       level,
+		Time:        time.Now(),
+		Message:     "%s",
+		MessageArgs: []interface{}{msg},
+		Args:        map[string]interface{}{},
+		callers:     entryCallers(2),
+	}
+}
+
+// Text returns the message formatted with the message arguments, without prefix and suffix
+func (this *ObjectLogEntry) Text() string {
+	return fmt.Sprintf(this.Message, this.MessageArgs...)
+}
+
+// Format formats the entry with the given formatter. If the formatter is nil, then the formatter of the
+// entry is used. If both are nil, then the text is returned enclosed by prefix and suffix.
+func (this *ObjectLogEntry) Format(formatter ObjectLogFormatter) string {
+	if formatter == nil {
+		formatter = this.Formatter
+	}
+	if formatter == nil {
+		return this.Prefix + this.Text() + this.Suffix
+	} else if isTemplateFormatter(formatter) {
+		return formatter(this.Level, this.Prefix, this.Suffix, "", []interface{}{templateEntryArg{this}}, this.LogArgs())
+	}
+	return formatter(this.Level, this.Prefix, this.Suffix, this.Message, this.MessageArgs, this.LogArgs())
+}
+
+// LogArgs returns the args overlaid by the fields of the message. The args are returned as is, if the
+// message has no fields, otherwise they are merged into a new map.
+func (this *ObjectLogEntry) LogArgs() map[string]interface{} {
+	if len(this.Fields) == 0 {
+		return this.Args
+	}
+	args := make(map[string]interface{}, len(this.Args)+len(this.Fields))
+	for k, v := range this.Args {
+		args[k] = v
+	}
+	for k, v := range this.Fields {
+		args[k] = v
+	}
+	return args
+}
+
+// String returns the entry formatted with it's own formatter
+func (this *ObjectLogEntry) String() string {
+	return this.Format(nil)
+}
+
+// Copy returns a shallow copy of the entry with own args, which can be modified. The fields are merged
+// into the args of the copy.
+func (this *ObjectLogEntry) Copy() *ObjectLogEntry {
+	entry := *this
+	entry.Args = make(map[string]interface{}, len(this.Args)+len(this.Fields))
+	for k, v := range this.Args {
+		entry.Args[k] = v
+	}
+	for k, v := range this.Fields {
+		entry.Args[k] = v
+	}
+	entry.Fields = nil
+	return &entry
+}
+
+// entryCallers returns the program counters of the call stack, skipping the given amount of frames of the
+// caller of entryCallers
+func entryCallers(skip int) []uintptr {
+	pcs := make([]uintptr, 16)
+	return pcs[:runtime.Callers(skip+2, pcs)]
+}
+
+// writeEntry passes the entry to loggers implementing `EntryLogger`, all others receive the formatted
+// entry with the method matching the level
+func writeEntry(logger ObjectLogger, entry *ObjectLogEntry) {
+	if l, ok := logger.(EntryLogger); ok {
+		l.LogEntry(entry)
+		return
+	}
+	writeLevel(logger, entry.Level, entry.String())
+}
+
+// entryLevelMethods implements all level methods by passing text entries to a function. It is embedded
+// by loggers which implement `EntryLogger`.
+type entryLevelMethods struct {
+	logEntry func(entry *ObjectLogEntry)
+}
+
+func (this entryLevelMethods) Trace(msg string) {
+	this.logEntry(NewTextEntry(OBJECT_LOG_LEVEL_TRACE, msg))
+}
+
+func (this entryLevelMethods) Debug(msg string) {
+	this.logEntry(NewTextEntry(OBJECT_LOG_LEVEL_DEBUG, msg))
+}
+
+func (this entryLevelMethods) Info(msg string) {
+	this.logEntry(NewTextEntry(OBJECT_LOG_LEVEL_INFO, msg))
+}
+
+func (this entryLevelMethods) Notice(msg string) {
+	this.logEntry(NewTextEntry(OBJECT_LOG_LEVEL_NOTICE, msg))
+}
+
+func (this entryLevelMethods) Warn(msg string) {
+	this.logEntry(NewTextEntry(OBJECT_LOG_LEVEL_WARN, msg))
+}
+
+func (this entryLevelMethods) Error(msg string) {
+	this.logEntry(NewTextEntry(OBJECT_LOG_LEVEL_ERROR, msg))
+}
+
+func (this entryLevelMethods) Panic(msg string) {
+	this.logEntry(NewTextEntry(OBJECT_LOG_LEVEL_PANIC, msg))
+}
+
+func (this entryLevelMethods) Fatal(msg string) {
+	this.logEntry(NewTextEntry(OBJECT_LOG_LEVEL_FATAL, msg))
+}
+
+func (this entryLevelMethods) Log(level ObjectLogLevel, msg string) {
+	this.logEntry(NewTextEntry(level, msg))
+}
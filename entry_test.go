@@ -0,0 +1,63 @@
+package objectlog
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testEntryLogger struct {
+	entryLevelMethods
+	entries []*ObjectLogEntry
+}
+
+func newTestEntryLogger() *testEntryLogger {
+	this := &testEntryLogger{}
+	this.entryLevelMethods = entryLevelMethods{func(entry *ObjectLogEntry) {
+		this.entries = append(this.entries, entry)
+	}}
+	return this
+}
+
+func (this *testEntryLogger) LogEntry(entry *ObjectLogEntry) {
+	this.entries = append(this.entries, entry)
+}
+
+func TestObjectLogEntry(t *testing.T) {
+	lg := newTestEntryLogger()
+	ol := NewObjectLog(lg).SetLogPrefix("PRE ").SetLogSuffix(" SUF").SetLogArg("foo", "bar")
+	ol.LogWith("baz", 1).LogInfo("Hello %s", "you")
+	if assert.Len(t, lg.entries, 1) {
+		entry := lg.entries[0]
+		assert.Equal(t, OBJECT_LOG_LEVEL_INFO, entry.Level)
+		assert.False(t, entry.Time.IsZero())
+		assert.Equal(t, "PRE ", entry.Prefix)
+		assert.Equal(t, " SUF", entry.Suffix)
+		assert.Equal(t, "Hello you", entry.Text())
+		assert.Equal(t, map[string]interface{}{"foo": "bar"}, entry.Args)
+		assert.Equal(t, map[string]interface{}{"baz": 1}, entry.Fields)
+		assert.Equal(t, map[string]interface{}{"foo": "bar", "baz": 1}, entry.LogArgs())
+		assert.Equal(t, `PRE Hello you SUF :: {"baz":1,"foo":"bar"}`, entry.String())
+		assert.Equal(t, "[info] Hello you", entry.Format(func(level ObjectLogLevel, prefix, suffix, msg string, msgArgs []interface{}, logArgs map[string]interface{}) string {
+			return "[" + string(level) + "] " + fmt.Sprintf(msg, msgArgs...)
+		}))
+
+		copied := entry.Copy()
+		assert.Equal(t, map[string]interface{}{"foo": "bar", "baz": 1}, copied.Args)
+		assert.Nil(t, copied.Fields)
+		copied.Args["other"] = true
+		assert.Equal(t, 1, len(entry.Args))
+	}
+}
+
+func TestNewTextEntry(t *testing.T) {
+	lg := newTestEntryLogger()
+	lg.Warn("100% done")
+	lg.Log(OBJECT_LOG_LEVEL_NOTICE, "notice")
+	if assert.Len(t, lg.entries, 2) {
+		assert.Equal(t, OBJECT_LOG_LEVEL_WARN, lg.entries[0].Level)
+		assert.Equal(t, "100% done", lg.entries[0].String())
+		assert.Equal(t, OBJECT_LOG_LEVEL_NOTICE, lg.entries[1].Level)
+		assert.Equal(t, "notice", lg.entries[1].String())
+	}
+}
@@ -0,0 +1,115 @@
+package objectlog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+
+	// OBJECT_LOG_ENV_LEVEL is the environment variable containing the global level, see `ParseLevel`
+	OBJECT_LOG_ENV_LEVEL = "OBJECTLOG_LEVEL"
+
+	// OBJECT_LOG_ENV_FORMAT is the environment variable containing the format: "text" (default), "console",
+	// "json" or "logfmt"
+	OBJECT_LOG_ENV_FORMAT = "OBJECTLOG_FORMAT"
+
+	// OBJECT_LOG_ENV_OUTPUT is the environment variable containing the output: "stderr" (default), "stdout" or
+	// "file:/path/to/file"
+	OBJECT_LOG_ENV_OUTPUT = "OBJECTLOG_OUTPUT"
+
+	// OBJECT_LOG_ENV_COLOR is the environment variable controlling colors of the "text" and "console"
+	// formats: "auto" (default), "on" or "off"
+	OBJECT_LOG_ENV_COLOR = "OBJECTLOG_COLOR"
+)
+
+/*
+InitFromEnv configures `DefaultLogger` and the global level from the environment variables `OBJECTLOG_LEVEL`,
+`OBJECTLOG_FORMAT`, `OBJECTLOG_OUTPUT` and `OBJECTLOG_COLOR`. It is opt-in and should be called early in `main`.
+If none of the variables is set, nothing is changed and the current `DefaultLogger` is returned.
+
+	func main() {
+		if _, err := objectlog.InitFromEnv(); err != nil {
+			panic(err)
+		}
+	}
+
+The following would write JSON lines of all messages at DEBUG or above to a file:
+
+	OBJECTLOG_LEVEL=debug OBJECTLOG_FORMAT=json OBJECTLOG_OUTPUT=file:/var/log/app.log ./app
+*/
+func InitFromEnv() (ObjectLogger, error) {
+	if os.Getenv(OBJECT_LOG_ENV_LEVEL) == "" && os.Getenv(OBJECT_LOG_ENV_FORMAT) == "" && os.Getenv(OBJECT_LOG_ENV_OUTPUT) == "" && os.Getenv(OBJECT_LOG_ENV_COLOR) == "" {
+		return CurrentDefaultLogger(), nil
+	}
+	level, err := ParseLevelEnv(OBJECT_LOG_ENV_LEVEL, "")
+	if err != nil {
+		return nil, envError(OBJECT_LOG_ENV_LEVEL, err)
+	}
+	writer, err := envOutput()
+	if err != nil {
+		return nil, envError(OBJECT_LOG_ENV_OUTPUT, err)
+	}
+	color, err := envColor(writer)
+	if err != nil {
+		return nil, envError(OBJECT_LOG_ENV_COLOR, err)
+	}
+	formatter, err := envFormatter(color)
+	if err != nil {
+		return nil, envError(OBJECT_LOG_ENV_FORMAT, err)
+	}
+	if level != "" {
+		SetGlobalLevel(level)
+	}
+	logger := NewWriterLogger(writer, formatter)
+	SetDefaultLogger(logger)
+	return logger, nil
+}
+
+func envError(name string, err error) error {
+	return fmt.Errorf("objectlog: invalid %s=%q: %s", name, os.Getenv(name), strings.TrimPrefix(err.Error(), "objectlog: "))
+}
+
+func envOutput() (io.Writer, error) {
+	switch output := os.Getenv(OBJECT_LOG_ENV_OUTPUT); {
+	case output == "" || output == "stderr":
+		return os.Stderr, nil
+	case output == "stdout":
+		return os.Stdout, nil
+	case strings.HasPrefix(output, "file:"):
+		name := strings.TrimPrefix(output, "file:")
+		if name == "" {
+			return nil, fmt.Errorf("missing path")
+		}
+		return os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	}
+	return nil, fmt.Errorf("expected stderr, stdout or file:<path>")
+}
+
+func envColor(writer io.Writer) (bool, error) {
+	switch strings.ToLower(os.Getenv(OBJECT_LOG_ENV_COLOR)) {
+	case "", "auto":
+		return UseColor(writer), nil
+	case "on", "true", "1":
+		return true, nil
+	case "off", "false", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected auto, on or off")
+}
+
+func envFormatter(color bool) (ObjectLogFormatter, error) {
+	switch strings.ToLower(os.Getenv(OBJECT_LOG_ENV_FORMAT)) {
+	case "", "text":
+		return NewTextFormatter(color), nil
+	case "console":
+		return NewConsoleFormatter(ConsoleOptions{Color: color, Relative: true}), nil
+	case "json":
+		return JSONFormatter, nil
+	case "logfmt":
+		return LogfmtFormatter, nil
+	}
+	return nil, fmt.Errorf("expected text, console, json or logfmt")
+}
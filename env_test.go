@@ -0,0 +1,100 @@
+package objectlog
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setTestEnv(env map[string]string) func() {
+	names := []string{OBJECT_LOG_ENV_LEVEL, OBJECT_LOG_ENV_FORMAT, OBJECT_LOG_ENV_OUTPUT, OBJECT_LOG_ENV_COLOR}
+	for _, name := range names {
+		os.Unsetenv(name)
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+	}
+	logger := DefaultLogger
+	return func() {
+		for _, name := range names {
+			os.Unsetenv(name)
+		}
+		DefaultLogger = logger
+		resetRuntimeLevels()
+	}
+}
+
+func TestInitFromEnv_Unset(t *testing.T) {
+	defer setTestEnv(nil)()
+	logger := DefaultLogger
+	lg, err := InitFromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, logger, lg)
+	assert.Equal(t, logger, DefaultLogger)
+}
+
+func TestInitFromEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "objectlog")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "out.log")
+	defer setTestEnv(map[string]string{
+		OBJECT_LOG_ENV_LEVEL:  "warning",
+		OBJECT_LOG_ENV_FORMAT: "JSON",
+		OBJECT_LOG_ENV_OUTPUT: "file:" + name,
+	})()
+
+	lg, err := InitFromEnv()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, lg, DefaultLogger)
+	assert.Equal(t, OBJECT_LOG_LEVEL_WARN, GlobalLevel())
+
+	ol := NewObjectLog().SetLogArg("foo", "bar")
+	ol.LogInfo("hidden")
+	ol.LogWarn("shown")
+	raw, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+	data := map[string]interface{}{}
+	if assert.Nil(t, json.Unmarshal(raw, &data)) {
+		assert.Equal(t, "shown", data["msg"])
+		assert.Equal(t, "warn", data["level"])
+		assert.Equal(t, "bar", data["foo"])
+	}
+}
+
+func TestInitFromEnv_Text(t *testing.T) {
+	defer setTestEnv(map[string]string{
+		OBJECT_LOG_ENV_OUTPUT: "stdout",
+		OBJECT_LOG_ENV_COLOR:  "off",
+	})()
+	lg, err := InitFromEnv()
+	if assert.Nil(t, err) {
+		assert.Equal(t, os.Stdout, lg.(*WriterLogger).Writer())
+	}
+}
+
+func TestInitFromEnv_Errors(t *testing.T) {
+	for name, expect := range map[string]string{
+		OBJECT_LOG_ENV_LEVEL:  `objectlog: invalid OBJECTLOG_LEVEL="bogus": unknown level "bogus"`,
+		OBJECT_LOG_ENV_FORMAT: `objectlog: invalid OBJECTLOG_FORMAT="bogus": expected text, console, json or logfmt`,
+		OBJECT_LOG_ENV_OUTPUT: `objectlog: invalid OBJECTLOG_OUTPUT="bogus": expected stderr, stdout or file:<path>`,
+		OBJECT_LOG_ENV_COLOR:  `objectlog: invalid OBJECTLOG_COLOR="bogus": expected auto, on or off`,
+	} {
+		reset := setTestEnv(map[string]string{name: "bogus"})
+		logger := DefaultLogger
+		lg, err := InitFromEnv()
+		assert.Nil(t, lg)
+		if assert.NotNil(t, err, name) {
+			assert.Equal(t, expect, err.Error())
+		}
+		assert.Equal(t, logger, DefaultLogger)
+		reset()
+	}
+}
@@ -0,0 +1,103 @@
+package objectlog
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+/*
+ErrorHandler receives the failures of the bundled loggers, which cannot return errors from the methods of
+`ObjectLogger`. For example, when the disk is full or the connection to a remote log server is lost. Each
+logger reports to its own handler, if set, and otherwise to the global handler. If neither is set, then the
+error is written to STDERR.
+
+	objectlog.SetErrorHandler(func(logger objectlog.ObjectLogger, err error) {
+		metrics.Increment("log_write_failures")
+	})
+	lg := objectlog.NewWriterLogger(fh, objectlog.JSONFormatter).SetErrorHandler(func(logger objectlog.ObjectLogger, err error) {
+		fmt.Fprintf(os.Stderr, "cannot write log file: %s\n", err)
+	})
+
+Callers which need the error of a single write use `EntryWriter`, which is implemented by most bundled loggers.
+Errors returned by `WriteEntry` are not reported to the handler.
+*/
+type (
+
+	// ErrorHandler is called with the logger, which failed to write a message, and the error
+	ErrorHandler func(logger ObjectLogger, err error)
+
+	// ErrorReporter is implemented by loggers, which report their failures to an `ErrorHandler`
+	ErrorReporter interface {
+		// Failures returns the amount of reported failures
+		Failures() uint64
+	}
+
+	// errorReporting implements `ErrorReporter`. It is embedded by loggers.
+	errorReporting struct {
+		errorMutex sync.RWMutex
+		handler    ErrorHandler
+		failures   uint64
+	}
+)
+
+var (
+	errorHandlerMutex sync.RWMutex
+	errorHandler      ErrorHandler
+	errorFailures     uint64
+)
+
+// SetErrorHandler sets the global handler of logger failures and returns the previous handler. If nil, then
+// failures are written to STDERR.
+func SetErrorHandler(handler ErrorHandler) ErrorHandler {
+	errorHandlerMutex.Lock()
+	defer errorHandlerMutex.Unlock()
+	previous := errorHandler
+	errorHandler = handler
+	return previous
+}
+
+// Failures returns the amount of failures reported by all loggers
+func Failures() uint64 {
+	errorHandlerMutex.RLock()
+	defer errorHandlerMutex.RUnlock()
+	return errorFailures
+}
+
+// Failures returns the amount of failures reported by the logger
+func (this *errorReporting) Failures() uint64 {
+	this.errorMutex.RLock()
+	defer this.errorMutex.RUnlock()
+	return this.failures
+}
+
+func (this *errorReporting) setErrorHandler(handler ErrorHandler) {
+	this.errorMutex.Lock()
+	defer this.errorMutex.Unlock()
+	this.handler = handler
+}
+
+// report counts the error and passes it to the handler of the logger or the global handler. Nil errors are
+// ignored.
+func (this *errorReporting) report(logger ObjectLogger, err error) {
+	if err == nil {
+		return
+	}
+	this.errorMutex.Lock()
+	this.failures++
+	handler := this.handler
+	this.errorMutex.Unlock()
+
+	errorHandlerMutex.Lock()
+	errorFailures++
+	if handler == nil {
+		handler = errorHandler
+	}
+	errorHandlerMutex.Unlock()
+
+	if handler != nil {
+		handler(logger, err)
+	} else {
+		fmt.Fprintf(os.Stderr, "objectlog: %T failed: %s\n", logger, err)
+	}
+}
@@ -0,0 +1,62 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"log"
+	"sync"
+	"testing"
+)
+
+// testErrorHandler records the reported errors
+type testErrorHandler struct {
+	mutex   sync.Mutex
+	loggers []ObjectLogger
+	errors  []string
+}
+
+func (this *testErrorHandler) Handle(logger ObjectLogger, err error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.loggers = append(this.loggers, logger)
+	this.errors = append(this.errors, err.Error())
+}
+
+func TestSetErrorHandler(t *testing.T) {
+	global := &testErrorHandler{}
+	defer SetErrorHandler(SetErrorHandler(global.Handle))
+	failures := Failures()
+
+	lg := NewWriterLogger(testFailingWriter{}, nil)
+	NewObjectLog(lg).LogInfo("hello")
+	assert.Equal(t, []ObjectLogger{lg}, global.loggers)
+	assert.Equal(t, []string{"disk full"}, global.errors)
+	assert.Equal(t, uint64(1), lg.Failures())
+	assert.Equal(t, failures+1, Failures())
+
+	own := &testErrorHandler{}
+	lg.SetErrorHandler(own.Handle)
+	lg.Warn("world")
+	assert.Equal(t, []string{"disk full"}, own.errors)
+	assert.Equal(t, 1, len(global.errors))
+	assert.Equal(t, uint64(2), lg.Failures())
+	assert.Equal(t, failures+2, Failures())
+
+	assert.NotNil(t, lg.WriteEntry(NewTextEntry(OBJECT_LOG_LEVEL_INFO, "not reported")))
+	assert.Equal(t, uint64(2), lg.Failures())
+}
+
+func TestStandardLogger_Errors(t *testing.T) {
+	handler := &testErrorHandler{}
+	lg := NewStandardLogger(log.New(testFailingWriter{}, "", 0)).SetErrorHandler(handler.Handle)
+	lg.Info("hello")
+	lg.Log(OBJECT_LOG_LEVEL_NOTICE, "hello")
+	assert.Equal(t, []string{"disk full", "disk full"}, handler.errors)
+	assert.Equal(t, uint64(2), lg.Failures())
+
+	err := lg.WriteEntry(NewTextEntry(OBJECT_LOG_LEVEL_INFO, "hello"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "disk full", err.Error())
+	}
+	var _ ErrorReporter = lg
+	var _ EntryWriter = lg
+}
@@ -28,7 +28,7 @@ var (
 // NewBrand showcases creation of a parental object, which is decorated by ObjectLog
 func NewBrand(name string) *Brand {
 	return &Brand{
-		ObjectLog: objectlog.NewObjectLog(logger).SetLogPrefix(fmt.Sprintf("Brand(%s): ", name)),
+		ObjectLog: objectlog.NewObjectLog(logger).SetLogName(name).SetLogPrefix(fmt.Sprintf("Brand(%s): ", name)),
 		Name:      name,
 	}
 }
@@ -36,7 +36,7 @@ func NewBrand(name string) *Brand {
 // NewCar showcases how to inherit a parent ObjectLog
 func (this *Brand) NewCar(model string) *Car {
 	return &Car{
-		ObjectLog: this.LogCloneObjectLog().SetLogPrefix(fmt.Sprintf("%sModel(%s): ", this.LogPrefix(), model)),
+		ObjectLog: this.LogChild(fmt.Sprintf("Model(%s)", model), map[string]interface{}{"model": model}),
 		Brand:     this,
 		Model:     model,
 	}
@@ -49,9 +49,13 @@ func main() {
 	brand2 := NewBrand("Ferrari")
 	car2 := brand2.NewCar("F-40")
 
-	// writes "Brand(DeLorean): Model(DMC-12): Wrumm, Wrumm"
+	// writes "Brand(DeLorean): Model(DMC-12): Wrumm, Wrumm :: {"model":"DMC-12"}"
 	car1.LogInfo("Wrumm, Wrumm")
 
-	// writes "Brand(Ferrari): Model(F-40): Roarr"
+	// writes "Brand(Ferrari): Model(F-40): Roarr :: {"model":"F-40"}"
 	car2.LogInfo("Roarr")
+
+	// changes on the parent propagate to the children: writes nothing
+	brand2.SetLogLevel(objectlog.OBJECT_LOG_LEVEL_WARN)
+	car2.LogInfo("Roarr again")
 }
@@ -0,0 +1,144 @@
+package objectlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+
+	// JSONFormatter formats the log message as single line JSON object with the keys "time", "level",
+	// "prefix" and "suffix" (only if not empty) and "msg". Log arguments are added as keys, those which
+	// conflict with the former are prefixed with "args.".
+	//	{"foo":"bar","level":"info","msg":"Hello","prefix":"Mr. Foo: ","time":"2039-12-24T23:59:59Z"}
+	JSONFormatter ObjectLogFormatter = func(level ObjectLogLevel, prefix, suffix, msg string, msgArgs []interface{}, logArgs map[string]interface{}) string {
+		return string(jsonRecord(time.Now(), level, prefix, suffix, fmt.Sprintf(msg, msgArgs...), logArgs))
+	}
+
+	// LogfmtFormatter formats the log message as logfmt line with the keys "time", "level", "prefix" and
+	// "suffix" (only if not empty) and "msg", followed by the log arguments in alphabetical order.
+	//	time=2039-12-24T23:59:59Z level=info prefix="Mr. Foo: " msg=Hello foo=bar
+	LogfmtFormatter ObjectLogFormatter = func(level ObjectLogLevel, prefix, suffix, msg string, msgArgs []interface{}, logArgs map[string]interface{}) string {
+		fields := formatterFields(time.Now(), level, prefix, suffix, fmt.Sprintf(msg, msgArgs...))
+		parts := []string{}
+		for _, key := range []string{"time", "level", "prefix", "msg", "suffix"} {
+			if value, ok := fields[key]; ok {
+				parts = append(parts, key+"="+logfmtValue(value))
+			}
+		}
+		if args := logfmtArgs(logArgs, ""); args != "" {
+			parts = append(parts, args)
+		}
+		return strings.Join(parts, " ")
+	}
+)
+
+// NewTextFormatter creates a formatter, which formats like `DefaultFormatter`, but starts with the current
+// time and the level name in brackets, optionally colored:
+//	2039/12/24 23:59:59 [INFO] Mr. Foo: Hello :: {"foo":"bar"}
+func NewTextFormatter(color bool) ObjectLogFormatter {
+	return func(level ObjectLogLevel, prefix, suffix, msg string, msgArgs []interface{}, logArgs map[string]interface{}) string {
+		tag := "[" + LevelName(level) + "]"
+		if color {
+			tag = colorize(levelColor(level), tag)
+		}
+		return time.Now().Format("2006/01/02 15:04:05") + " " + tag + " " + DefaultFormatter(level, prefix, suffix, msg, msgArgs, logArgs)
+	}
+}
+
+// jsonRecord renders the JSON object of `JSONFormatter`. If the log arguments cannot be encoded, then
+// they are left out.
+func jsonRecord(ts time.Time, level ObjectLogLevel, prefix, suffix, text string, logArgs map[string]interface{}) []byte {
+	fields := formatterFields(ts, level, prefix, suffix, text)
+	raw, err := json.Marshal(mergeRecord(fields, logArgs))
+	if err != nil {
+		raw, _ = json.Marshal(fields)
+	}
+	return raw
+}
+
+// mergeRecord adds the log arguments to the fixed fields. Arguments, which conflict with fixed fields,
+// are prefixed with "args.".
+func mergeRecord(fields, logArgs map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, len(logArgs)+len(fields))
+	for k, v := range logArgs {
+		data[k] = v
+	}
+	for k, v := range fields {
+		if existing, ok := data[k]; ok {
+			data["args."+k] = existing
+		}
+		data[k] = v
+	}
+	return data
+}
+
+// formatterFields returns the fixed fields of structured formats
+func formatterFields(ts time.Time, level ObjectLogLevel, prefix, suffix, text string) map[string]interface{} {
+	fields := map[string]interface{}{
+		"time":  ts.Format(time.RFC3339Nano),
+		"level": string(level),
+		"msg":   text,
+	}
+	if prefix != "" {
+		fields["prefix"] = prefix
+	}
+	if suffix != "" {
+		fields["suffix"] = suffix
+	}
+	return fields
+}
+
+// logfmtArgs renders the args as space separated `key=value` pairs in alphabetical order, with optionally
+// colored keys
+func logfmtArgs(args map[string]interface{}, keyColor string) string {
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = colorize(keyColor, logfmtKey(key)+"=") + logfmtValue(args[key])
+	}
+	return strings.Join(parts, " ")
+}
+
+// logfmtKey removes all characters, which are not allowed in keys
+func logfmtKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue renders a value, quoted if required
+func logfmtValue(value interface{}) string {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case error:
+		str = v.Error()
+	case fmt.Stringer:
+		str = v.String()
+	case nil:
+		return "null"
+	default:
+		if raw, err := json.Marshal(v); err == nil {
+			str = string(raw)
+		} else {
+			str = fmt.Sprint(v)
+		}
+	}
+	if str == "" || strings.ContainsAny(str, " =\"\\") || strings.IndexFunc(str, func(r rune) bool { return r < ' ' }) >= 0 {
+		return strconv.Quote(str)
+	}
+	return str
+}
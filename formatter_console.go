@@ -0,0 +1,76 @@
+package objectlog
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type (
+
+	// ConsoleOptions configure the formatter created by `NewConsoleFormatter`
+	ConsoleOptions struct {
+
+		// Color enables ANSI colors
+		Color bool
+
+		// Relative prepends the time passed since the creation of the formatter
+		Relative bool
+
+		// TimeFormat prepends the current time in the format, if not empty and not `Relative`
+		TimeFormat string
+	}
+)
+
+/*
+NewConsoleFormatter creates a human friendly formatter for local development. Each line starts with the optional
+time, followed by the level name padded to the same width for all levels. The prefix is dimmed and the log
+arguments are appended as `key=value` pairs in alphabetical order, with highlighted keys:
+
+  - 1.234s INFO   Mr. Foo: Hello you foo=bar
+  - 1.240s NOTICE Mr. Foo: Hi there
+*/
+func NewConsoleFormatter(options ConsoleOptions) ObjectLogFormatter {
+	start := time.Now()
+	width := 0
+	for _, level := range Levels() {
+		if l := len(LevelName(level)); l > width {
+			width = l
+		}
+	}
+	return func(level ObjectLogLevel, prefix, suffix, msg string, msgArgs []interface{}, logArgs map[string]interface{}) string {
+		parts := []string{}
+		if options.Relative {
+			parts = append(parts, fmt.Sprintf("+%8.3fs", time.Since(start).Seconds()))
+		} else if options.TimeFormat != "" {
+			parts = append(parts, time.Now().Format(options.TimeFormat))
+		}
+		parts = append(parts, consoleColor(options.Color, levelColor(level), fmt.Sprintf("%-*s", width, LevelName(level))))
+		text := consoleColor(options.Color, colorDim, prefix) + fmt.Sprintf(msg, msgArgs...) + suffix
+		if len(logArgs) > 0 {
+			keyColor := ""
+			if options.Color {
+				keyColor = colorCyan
+			}
+			text += " " + logfmtArgs(logArgs, keyColor)
+		}
+		return strings.Join(append(parts, text), " ")
+	}
+}
+
+// NewConsoleFormatterFor creates a console formatter with relative timestamps, which uses colors only if the
+// writer is a terminal and `NO_COLOR` is not set, see `UseColor`.
+func NewConsoleFormatterFor(writer io.Writer) ObjectLogFormatter {
+	return NewConsoleFormatter(ConsoleOptions{
+		Color:    UseColor(writer),
+		Relative: true,
+	})
+}
+
+func consoleColor(enabled bool, color, text string) string {
+	if !enabled {
+		return text
+	}
+	return colorize(color, text)
+}
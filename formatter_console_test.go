@@ -0,0 +1,34 @@
+package objectlog
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestConsoleFormatter(t *testing.T) {
+	formatter := NewConsoleFormatter(ConsoleOptions{})
+	args := map[string]interface{}{"foo": "bar", "say": "hello you"}
+	assert.Equal(t, `INFO   pre hello you suf foo=bar say="hello you"`, formatter(OBJECT_LOG_LEVEL_INFO, "pre ", " suf", "hello %s", []interface{}{"you"}, args))
+	assert.Equal(t, `NOTICE hello`, formatter(OBJECT_LOG_LEVEL_NOTICE, "", "", "hello", nil, nil))
+}
+
+func TestConsoleFormatter_Color(t *testing.T) {
+	formatter := NewConsoleFormatter(ConsoleOptions{Color: true})
+	assert.Equal(t, colorYellow+"WARN  "+colorReset+" "+colorDim+"pre "+colorReset+"hello "+colorCyan+"foo="+colorReset+"bar",
+		formatter(OBJECT_LOG_LEVEL_WARN, "pre ", "", "hello", nil, map[string]interface{}{"foo": "bar"}))
+}
+
+func TestConsoleFormatter_Time(t *testing.T) {
+	out := NewConsoleFormatter(ConsoleOptions{Relative: true})(OBJECT_LOG_LEVEL_DEBUG, "", "", "hello", nil, nil)
+	assert.Regexp(t, regexp.MustCompile(`^\+\s+0\.\d{3}s DEBUG  hello$`), out)
+	out = NewConsoleFormatter(ConsoleOptions{TimeFormat: "15:04:05"})(OBJECT_LOG_LEVEL_DEBUG, "", "", "hello", nil, nil)
+	assert.Regexp(t, regexp.MustCompile(`^\d\d:\d\d:\d\d DEBUG  hello$`), out)
+}
+
+func TestConsoleFormatterFor(t *testing.T) {
+	out := NewConsoleFormatterFor(&bytes.Buffer{})(OBJECT_LOG_LEVEL_ERROR, "", "", "hello", nil, nil)
+	assert.NotContains(t, out, "\x1b")
+	assert.Regexp(t, regexp.MustCompile(`^\+\s+0\.\d{3}s ERROR  hello$`), out)
+}
@@ -0,0 +1,87 @@
+package objectlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+)
+
+var (
+
+	// GelfFormatter formats the log message as GELF 1.1 JSON, see `NewGelfFormatter`. The host is the
+	// hostname of the machine.
+	GelfFormatter = NewGelfFormatter("")
+
+	// gelfInvalidChars matches characters, which are not allowed in names of additional fields
+	gelfInvalidChars = regexp.MustCompile(`[^\w.\-]`)
+)
+
+// NewGelfFormatter creates a formatter, which renders the log message as GELF 1.1 JSON
+// (http://docs.graylog.org/en/latest/pages/gelf.html). The level is mapped to the syslog severity, prefix,
+// suffix and log arguments are added as additional fields with a leading underscore. If host is empty, then
+// the hostname of the machine is used.
+//	{"version":"1.1","host":"localhost","short_message":"Hello","timestamp":1.5,"level":6,"_prefix":"Mr. Foo: ","_foo":"bar"}
+func NewGelfFormatter(host string) ObjectLogFormatter {
+	host = gelfHost(host)
+	return func(level ObjectLogLevel, prefix, suffix, msg string, msgArgs []interface{}, logArgs map[string]interface{}) string {
+		return string(gelfMessage(host, time.Now(), level, prefix, suffix, fmt.Sprintf(msg, msgArgs...), logArgs))
+	}
+}
+
+// SyslogSeverity returns the syslog severity (0 = emergency .. 7 = debug) of the level, determined by the
+// severity of the level.
+func SyslogSeverity(level ObjectLogLevel) int {
+	switch severity := level.Severity(); {
+	case severity < OBJECT_LOG_LEVEL_INFO.Severity():
+		return 7
+	case severity < OBJECT_LOG_LEVEL_NOTICE.Severity():
+		return 6
+	case severity < OBJECT_LOG_LEVEL_WARN.Severity():
+		return 5
+	case severity < OBJECT_LOG_LEVEL_ERROR.Severity():
+		return 4
+	case severity < OBJECT_LOG_LEVEL_PANIC.Severity():
+		return 3
+	}
+	return 2
+}
+
+func gelfHost(host string) string {
+	if host != "" {
+		return host
+	} else if hostname, err := os.Hostname(); err == nil {
+		return hostname
+	}
+	return "localhost"
+}
+
+// gelfMessage renders the GELF 1.1 JSON. Arguments, which cannot be encoded, are rendered with `fmt`.
+func gelfMessage(host string, ts time.Time, level ObjectLogLevel, prefix, suffix, msg string, logArgs map[string]interface{}) []byte {
+	data := map[string]interface{}{
+		"version":       "1.1",
+		"host":          host,
+		"short_message": msg,
+		"timestamp":     float64(ts.UnixNano()/int64(time.Millisecond)) / 1000,
+		"level":         SyslogSeverity(level),
+	}
+	if prefix != "" {
+		data["_prefix"] = prefix
+	}
+	if suffix != "" {
+		data["_suffix"] = suffix
+	}
+	for key, value := range logArgs {
+		key = "_" + gelfInvalidChars.ReplaceAllString(key, "_")
+		if key == "_id" {
+			key = "__id"
+		}
+		if _, err := json.Marshal(value); err != nil {
+			value = fmt.Sprint(value)
+		}
+		data[key] = value
+	}
+	raw, _ := json.Marshal(data)
+	return raw
+}
@@ -0,0 +1,50 @@
+package objectlog
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGelfFormatter(t *testing.T) {
+	out := NewGelfFormatter("myhost")(OBJECT_LOG_LEVEL_WARN, "pre ", " suf", "hello %s", []interface{}{"you"}, map[string]interface{}{
+		"foo":     "bar",
+		"id":      1,
+		"a b":     true,
+		"invalid": func() {},
+	})
+	data := map[string]interface{}{}
+	if assert.Nil(t, json.Unmarshal([]byte(out), &data)) {
+		assert.NotZero(t, data["timestamp"])
+		delete(data, "timestamp")
+		assert.Equal(t, "0x", data["_invalid"].(string)[:2])
+		delete(data, "_invalid")
+		assert.Equal(t, map[string]interface{}{
+			"version":       "1.1",
+			"host":          "myhost",
+			"short_message": "hello you",
+			"level":         float64(4),
+			"_prefix":       "pre ",
+			"_suffix":       " suf",
+			"_foo":          "bar",
+			"__id":          float64(1),
+			"_a_b":          true,
+		}, data)
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	for level, expect := range map[ObjectLogLevel]int{
+		OBJECT_LOG_LEVEL_TRACE:  7,
+		OBJECT_LOG_LEVEL_DEBUG:  7,
+		OBJECT_LOG_LEVEL_INFO:   6,
+		OBJECT_LOG_LEVEL_NOTICE: 5,
+		OBJECT_LOG_LEVEL_WARN:   4,
+		OBJECT_LOG_LEVEL_ERROR:  3,
+		OBJECT_LOG_LEVEL_PANIC:  2,
+		OBJECT_LOG_LEVEL_FATAL:  2,
+		"unknown":               6,
+	} {
+		assert.Equal(t, expect, SyslogSeverity(level), string(level))
+	}
+}
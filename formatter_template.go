@@ -0,0 +1,182 @@
+package objectlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+)
+
+type (
+
+	// TemplateEntry is the data a template of `NewTemplateFormatter` is executed with
+	TemplateEntry struct {
+		Level   ObjectLogLevel
+		Prefix  string
+		Suffix  string
+		Message string
+		Args    map[string]interface{}
+		Time    time.Time
+		pcs     []uintptr
+	}
+
+	// TemplateCaller describes the code location, which wrote a log message
+	TemplateCaller struct {
+		File     string
+		Line     int
+		Function string
+		Package  string
+	}
+
+	// templateFormatter executes the template of `NewTemplateFormatter`
+	templateFormatter struct {
+		tmpl *template.Template
+	}
+
+	// templateEntryArg is passed as only message argument by `ObjectLogEntry.Format`, so that the template
+	// renders the time and caller of the entry, instead of those of the formatting
+	templateEntryArg struct {
+		entry *ObjectLogEntry
+	}
+)
+
+var (
+
+	// templateFormatterPointer identifies formatters of `NewTemplateFormatter`, see `isTemplateFormatter`
+	templateFormatterPointer = reflect.ValueOf((&templateFormatter{}).format).Pointer()
+
+	// TemplateFuncs are available in all templates of `NewTemplateFormatter`
+	TemplateFuncs = template.FuncMap{
+		"upper":  strings.ToUpper,
+		"lower":  strings.ToLower,
+		"pad":    templatePad,
+		"json":   templateJSON,
+		"logfmt": templateLogfmt,
+		"time":   templateTime,
+	}
+)
+
+/*
+NewTemplateFormatter creates a formatter from a `text/template`, which is executed with a `TemplateEntry`. Besides
+the functions of `text/template` and `TemplateFuncs` are available:
+
+	upper <string>         upper case
+	lower <string>         lower case
+	pad <width> <value>    pads the value with spaces to the width, left aligned unless the width is negative
+	json <value>           JSON encoded value
+	logfmt <value>         logfmt encoded args map or value
+	time <layout> <time>   formats time with the layout
+
+The template is parsed and executed with sample data once, so that errors are returned on construction. Time
+and caller are those of the `ObjectLogEntry` when it was written, if the formatter is used by its `Format`.
+Otherwise they are determined when the message is formatted.
+
+	formatter, err := objectlog.NewTemplateFormatter(`{{ time "15:04:05" .Time }} {{ pad 5 (upper .LevelName) }} {{ .Caller }} {{ .Prefix }}{{ .Message }} {{ logfmt .Args }}`)
+*/
+func NewTemplateFormatter(text string) (ObjectLogFormatter, error) {
+	tmpl, err := template.New("objectlog").Funcs(TemplateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	sample := &TemplateEntry{
+		Level: OBJECT_LOG_LEVEL_INFO,
+		Args:  map[string]interface{}{"key": "value"},
+		Time:  time.Now(),
+	}
+	if err := tmpl.Execute(&bytes.Buffer{}, sample); err != nil {
+		return nil, err
+	}
+	return (&templateFormatter{tmpl}).format, nil
+}
+
+func (this *templateFormatter) format(level ObjectLogLevel, prefix, suffix, msg string, msgArgs []interface{}, logArgs map[string]interface{}) string {
+	if logArgs == nil {
+		logArgs = map[string]interface{}{}
+	}
+	entry := &TemplateEntry{
+		Level:  level,
+		Prefix: prefix,
+		Suffix: suffix,
+		Args:   logArgs,
+	}
+	if arg, ok := templateEntryOf(msgArgs); ok {
+		msg, msgArgs = arg.entry.Message, arg.entry.MessageArgs
+		entry.Time, entry.pcs = arg.entry.Time, arg.entry.callers
+	} else {
+		entry.Time, entry.pcs = time.Now(), make([]uintptr, 16)
+		entry.pcs = entry.pcs[:runtime.Callers(2, entry.pcs)]
+	}
+	entry.Message = fmt.Sprintf(msg, msgArgs...)
+	buf := &bytes.Buffer{}
+	if err := this.tmpl.Execute(buf, entry); err != nil {
+		return fmt.Sprintf("!TEMPLATE(%s) %s", err, DefaultFormatter(level, prefix, suffix, msg, msgArgs, logArgs))
+	}
+	return buf.String()
+}
+
+// isTemplateFormatter returns whether the formatter was created by `NewTemplateFormatter`. All of them share
+// the code pointer of the method value.
+func isTemplateFormatter(formatter ObjectLogFormatter) bool {
+	return reflect.ValueOf(formatter).Pointer() == templateFormatterPointer
+}
+
+func templateEntryOf(msgArgs []interface{}) (templateEntryArg, bool) {
+	if len(msgArgs) != 1 {
+		return templateEntryArg{}, false
+	}
+	arg, ok := msgArgs[0].(templateEntryArg)
+	return arg, ok
+}
+
+// LevelName returns the display name of the level, see `LevelName`
+func (this *TemplateEntry) LevelName() string {
+	return LevelName(this.Level)
+}
+
+// Caller returns the code location outside of this package, which wrote the log message. It is only
+// resolved, if used in the template.
+func (this *TemplateEntry) Caller() TemplateCaller {
+	frame, ok := externalFrame(this.pcs)
+	if !ok {
+		return TemplateCaller{}
+	}
+	return TemplateCaller{
+		File:     frame.File,
+		Line:     frame.Line,
+		Function: frame.Function,
+		Package:  framePackage(frame.Function),
+	}
+}
+
+// String returns `<file base name>:<line>`
+func (this TemplateCaller) String() string {
+	if this.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", filepath.Base(this.File), this.Line)
+}
+
+func templatePad(width int, value interface{}) string {
+	return fmt.Sprintf("%*s", -width, fmt.Sprint(value))
+}
+
+func templateJSON(value interface{}) (string, error) {
+	raw, err := json.Marshal(value)
+	return string(raw), err
+}
+
+func templateLogfmt(value interface{}) string {
+	if args, ok := value.(map[string]interface{}); ok {
+		return logfmtArgs(args, "")
+	}
+	return logfmtValue(value)
+}
+
+func templateTime(layout string, value time.Time) string {
+	return value.Format(layout)
+}
@@ -0,0 +1,74 @@
+package objectlog
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateFormatter(t *testing.T) {
+	formatter, err := NewTemplateFormatter(`{{ pad 6 (upper .LevelName) }}|{{ pad -3 "x" }}|{{ .Prefix }}{{ .Message }}{{ .Suffix }}|{{ logfmt .Args }}|{{ json .Args }}|{{ .Args.foo }}`)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, `WARN  |  x|pre hello you suf|foo=bar n=1|{"foo":"bar","n":1}|bar`,
+		formatter(OBJECT_LOG_LEVEL_WARN, "pre ", " suf", "hello %s", []interface{}{"you"}, map[string]interface{}{"foo": "bar", "n": 1}))
+	assert.Equal(t, `INFO  |  x|hello|foo=""|{"foo":""}|`, formatter(OBJECT_LOG_LEVEL_INFO, "", "", "hello", nil, map[string]interface{}{"foo": ""}))
+
+	formatter, err = NewTemplateFormatter(`{{ .Message }} {{ logfmt .Args }}{{ json .Args }}`)
+	if assert.Nil(t, err) {
+		assert.Equal(t, `hello {}`, formatter(OBJECT_LOG_LEVEL_INFO, "", "", "hello", nil, nil))
+	}
+}
+
+func TestTemplateFormatter_Time(t *testing.T) {
+	formatter, err := NewTemplateFormatter(`{{ time "2006-01-02" .Time }} {{ lower .Level.String }}`)
+	if assert.Nil(t, err) {
+		assert.Equal(t, time.Now().Format("2006-01-02")+" debug", formatter(OBJECT_LOG_LEVEL_DEBUG, "", "", "", nil, nil))
+	}
+}
+
+func TestTemplateFormatter_Caller(t *testing.T) {
+	formatter, err := NewTemplateFormatter(`{{ .Caller }} {{ .Caller.Package }} {{ .Message }}`)
+	if !assert.Nil(t, err) {
+		return
+	}
+	buf := NewBufferObjectLog()
+	ol := NewObjectLog(buf).SetLogFormatter(formatter)
+	ol.LogInfo("hello")
+	assert.Regexp(t, regexp.MustCompile(`^\[INF\] formatter_template_test\.go:\d+ github\.com/ukautz/objectlog hello\n$`), buf.Buffer().String())
+}
+
+func TestTemplateFormatter_Entry(t *testing.T) {
+	formatter, err := NewTemplateFormatter(`{{ time "15:04:05.000000000" .Time }} {{ .Caller }} {{ .Message }}`)
+	if !assert.Nil(t, err) {
+		return
+	}
+	buf := NewBufferObjectLog()
+	multi := NewMultiLogger(NewFilterLogger(buf, LevelFilter(OBJECT_LOG_LEVEL_INFO))).SetParallel(true)
+	ol := NewObjectLog(NewHookLogger(multi, NewArgsHook(map[string]interface{}{"foo": "bar"}))).SetLogFormatter(formatter)
+	_, _, line, _ := runtime.Caller(0)
+	ol.LogWith("n", 1).LogInfo("hello")
+	multi.Close()
+	if assert.Len(t, buf.Entries(), 1) {
+		entry := buf.Last()
+		expect := fmt.Sprintf("%s formatter_template_test.go:%d hello", entry.Time.Format("15:04:05.000000000"), line+1)
+		assert.Equal(t, expect, entry.String(), "renders time and caller of the entry")
+		assert.Equal(t, "[INF] "+expect+"\n", buf.String())
+	}
+}
+
+func TestTemplateFormatter_Errors(t *testing.T) {
+	_, err := NewTemplateFormatter(`{{ .Message `)
+	assert.NotNil(t, err)
+	_, err = NewTemplateFormatter(`{{ .Nope }}`)
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "Nope"), err.Error())
+	}
+	_, err = NewTemplateFormatter(`{{ nope }}`)
+	assert.NotNil(t, err)
+}
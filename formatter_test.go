@@ -0,0 +1,59 @@
+package objectlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestJSONFormatter(t *testing.T) {
+	out := JSONFormatter(OBJECT_LOG_LEVEL_WARN, "pre ", "", "hello %s", []interface{}{"you"}, map[string]interface{}{
+		"foo":   "bar",
+		"num":   2,
+		"level": "mine",
+	})
+	data := map[string]interface{}{}
+	if assert.Nil(t, json.Unmarshal([]byte(out), &data)) {
+		assert.NotEmpty(t, data["time"])
+		delete(data, "time")
+		assert.Equal(t, map[string]interface{}{
+			"level":      "warn",
+			"prefix":     "pre ",
+			"msg":        "hello you",
+			"foo":        "bar",
+			"num":        float64(2),
+			"args.level": "mine",
+		}, data)
+	}
+}
+
+func TestJSONFormatter_Unmarshalable(t *testing.T) {
+	out := JSONFormatter(OBJECT_LOG_LEVEL_INFO, "", "", "hello", nil, map[string]interface{}{"fn": func() {}})
+	data := map[string]interface{}{}
+	if assert.Nil(t, json.Unmarshal([]byte(out), &data)) {
+		assert.Equal(t, "hello", data["msg"])
+		assert.NotContains(t, data, "fn")
+	}
+}
+
+func TestLogfmtFormatter(t *testing.T) {
+	out := LogfmtFormatter(OBJECT_LOG_LEVEL_INFO, "Mr. Foo: ", "", "hello %s", []interface{}{"you"}, map[string]interface{}{
+		"foo":   "bar",
+		"empty": "",
+		"err":   fmt.Errorf("it \"failed\""),
+		"num":   1.5,
+		"a b":   true,
+		"nil":   nil,
+	})
+	assert.Regexp(t, regexp.MustCompile(`^time=\S+ level=info prefix="Mr. Foo: " msg="hello you" a_b=true empty="" err="it \\"failed\\"" foo=bar nil=null num=1.5$`), out)
+}
+
+func TestTextFormatter(t *testing.T) {
+	out := NewTextFormatter(false)(OBJECT_LOG_LEVEL_WARN, "pre ", "", "hello", nil, map[string]interface{}{"foo": "bar"})
+	assert.Regexp(t, regexp.MustCompile(`^\d{4}/\d\d/\d\d \d\d:\d\d:\d\d \[WARN\] pre hello :: {"foo":"bar"}$`), out)
+
+	out = NewTextFormatter(true)(OBJECT_LOG_LEVEL_ERROR, "", "", "hello", nil, nil)
+	assert.Contains(t, out, " "+colorRed+"[ERROR]"+colorReset+" hello")
+}
@@ -0,0 +1,306 @@
+package objectlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+
+	// TraceLogger can be implemented by an `ObjectLogger` to support the TRACE level. Otherwise
+	// TRACE messages are written with `Debug`.
+	TraceLogger interface {
+		// Trace writes trace level log message
+		Trace(msg string)
+	}
+
+	// NoticeLogger can be implemented by an `ObjectLogger` to support the NOTICE level. Otherwise
+	// NOTICE messages are written with `Info`.
+	NoticeLogger interface {
+		// Notice writes notice level log message
+		Notice(msg string)
+	}
+
+	// PanicLogger can be implemented by an `ObjectLogger` to support the PANIC level. Otherwise
+	// PANIC messages are written with `Error`.
+	PanicLogger interface {
+		// Panic writes panic level log message. It MUST NOT panic, `ObjectLog` panics after writing.
+		Panic(msg string)
+	}
+
+	// LevelLogger can be implemented by an `ObjectLogger` to support any registered level, including
+	// user-defined levels. It is used for all levels which have no dedicated method.
+	LevelLogger interface {
+		// Log writes log message in the given level
+		Log(level ObjectLogLevel, msg string)
+	}
+
+	objectLogLevelInfo struct {
+		severity int
+		name     string
+	}
+)
+
+const (
+	OBJECT_LOG_LEVEL_TRACE  ObjectLogLevel = "trace"
+	OBJECT_LOG_LEVEL_NOTICE ObjectLogLevel = "notice"
+	OBJECT_LOG_LEVEL_PANIC  ObjectLogLevel = "panic"
+)
+
+var (
+	levelsMutex sync.RWMutex
+	levels      = map[ObjectLogLevel]objectLogLevelInfo{
+		OBJECT_LOG_LEVEL_TRACE:  {10, "TRACE"},
+		OBJECT_LOG_LEVEL_DEBUG:  {20, "DEBUG"},
+		OBJECT_LOG_LEVEL_INFO:   {30, "INFO"},
+		OBJECT_LOG_LEVEL_NOTICE: {35, "NOTICE"},
+		OBJECT_LOG_LEVEL_WARN:   {40, "WARN"},
+		OBJECT_LOG_LEVEL_ERROR:  {50, "ERROR"},
+		OBJECT_LOG_LEVEL_PANIC:  {55, "PANIC"},
+		OBJECT_LOG_LEVEL_FATAL:  {60, "FATAL"},
+	}
+
+	// levelAliases are alternative, lower case names used by `ParseLevel`
+	levelAliases = map[string]ObjectLogLevel{
+		"trc":         OBJECT_LOG_LEVEL_TRACE,
+		"dbg":         OBJECT_LOG_LEVEL_DEBUG,
+		"inf":         OBJECT_LOG_LEVEL_INFO,
+		"information": OBJECT_LOG_LEVEL_INFO,
+		"ntc":         OBJECT_LOG_LEVEL_NOTICE,
+		"wrn":         OBJECT_LOG_LEVEL_WARN,
+		"warning":     OBJECT_LOG_LEVEL_WARN,
+		"err":         OBJECT_LOG_LEVEL_ERROR,
+		"pnc":         OBJECT_LOG_LEVEL_PANIC,
+		"crit":        OBJECT_LOG_LEVEL_FATAL,
+		"critical":    OBJECT_LOG_LEVEL_FATAL,
+		"ftl":         OBJECT_LOG_LEVEL_FATAL,
+	}
+
+	// fallbackLevels are used, in order of severity, for levels which are not supported by a logger.
+	// FATAL is not included, to never exit on a level which is not FATAL.
+	fallbackLevels = []ObjectLogLevel{
+		OBJECT_LOG_LEVEL_DEBUG,
+		OBJECT_LOG_LEVEL_INFO,
+		OBJECT_LOG_LEVEL_WARN,
+		OBJECT_LOG_LEVEL_ERROR,
+	}
+)
+
+// RegisterLevel adds a user-defined level, or replaces an existing one. The severity orders the level
+// in respect to the built-in levels, which range from TRACE (10) over DEBUG (20), INFO (30),
+// NOTICE (35), WARN (40), ERROR (50) and PANIC (55) to FATAL (60). The name is used for display.
+//	const AUDIT objectlog.ObjectLogLevel = "audit"
+//	objectlog.RegisterLevel(AUDIT, 45, "AUDIT")
+//	obj.Log(AUDIT, "User %s logged in", user)
+func RegisterLevel(level ObjectLogLevel, severity int, name string) {
+	levelsMutex.Lock()
+	defer levelsMutex.Unlock()
+	levels[level] = objectLogLevelInfo{severity, name}
+}
+
+// RegisterLevelAlias adds an alternative name for a level, which is understood by `ParseLevel`
+//	objectlog.RegisterLevelAlias("verbose", objectlog.OBJECT_LOG_LEVEL_DEBUG)
+func RegisterLevelAlias(alias string, level ObjectLogLevel) {
+	levelsMutex.Lock()
+	defer levelsMutex.Unlock()
+	levelAliases[strings.ToLower(alias)] = level
+}
+
+// ParseLevel returns the level of the given name. Names are case-insensitive and can be any registered
+// level, the display name of a registered level or an alias like "warning", "err" or "crit".
+//	level, err := objectlog.ParseLevel("WARNING") // OBJECT_LOG_LEVEL_WARN
+func ParseLevel(name string) (ObjectLogLevel, error) {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	levelsMutex.RLock()
+	defer levelsMutex.RUnlock()
+	if level, ok := levelAliases[lower]; ok {
+		return level, nil
+	}
+	for level, info := range levels {
+		if strings.ToLower(string(level)) == lower || strings.ToLower(info.name) == lower {
+			return level, nil
+		}
+	}
+	return "", fmt.Errorf("objectlog: unknown level %q", name)
+}
+
+// ParseLevelEnv returns the level from the named environment variable, or the fallback level if the
+// variable is not set or empty
+//	level, err := objectlog.ParseLevelEnv("LOG_LEVEL", objectlog.OBJECT_LOG_LEVEL_INFO)
+func ParseLevelEnv(name string, fallback ObjectLogLevel) (ObjectLogLevel, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return ParseLevel(value)
+}
+
+// LevelSeverity returns the severity of a level and whether the level is registered
+func LevelSeverity(level ObjectLogLevel) (int, bool) {
+	levelsMutex.RLock()
+	defer levelsMutex.RUnlock()
+	info, ok := levels[level]
+	return info.severity, ok
+}
+
+// LevelName returns the display name of a level, e.g. "DEBUG". Unregistered levels are upper cased.
+func LevelName(level ObjectLogLevel) string {
+	levelsMutex.RLock()
+	defer levelsMutex.RUnlock()
+	if info, ok := levels[level]; ok {
+		return info.name
+	}
+	return strings.ToUpper(string(level))
+}
+
+// Levels returns all registered levels, ordered from least to most severe
+func Levels() []ObjectLogLevel {
+	levelsMutex.RLock()
+	list := make([]ObjectLogLevel, 0, len(levels))
+	for level := range levels {
+		list = append(list, level)
+	}
+	levelsMutex.RUnlock()
+	sort.Slice(list, func(i, j int) bool {
+		return levelSeverity(list[i]) < levelSeverity(list[j])
+	})
+	return list
+}
+
+// levelSeverity returns the severity of a level. Unregistered levels are treated as INFO.
+func levelSeverity(level ObjectLogLevel) int {
+	if severity, ok := LevelSeverity(level); ok {
+		return severity
+	}
+	severity, _ := LevelSeverity(OBJECT_LOG_LEVEL_INFO)
+	return severity
+}
+
+// writeLevel writes the message with the method of the logger matching the level. Loggers which do not
+// support the level write with the method of the nearest less or equal severe level, if any, or DEBUG.
+func writeLevel(logger ObjectLogger, level ObjectLogLevel, msg string) {
+	switch level {
+	case OBJECT_LOG_LEVEL_DEBUG:
+		logger.Debug(msg)
+		return
+	case OBJECT_LOG_LEVEL_INFO:
+		logger.Info(msg)
+		return
+	case OBJECT_LOG_LEVEL_WARN:
+		logger.Warn(msg)
+		return
+	case OBJECT_LOG_LEVEL_ERROR:
+		logger.Error(msg)
+		return
+	case OBJECT_LOG_LEVEL_FATAL:
+		logger.Fatal(msg)
+		return
+	case OBJECT_LOG_LEVEL_TRACE:
+		if l, ok := logger.(TraceLogger); ok {
+			l.Trace(msg)
+			return
+		}
+	case OBJECT_LOG_LEVEL_NOTICE:
+		if l, ok := logger.(NoticeLogger); ok {
+			l.Notice(msg)
+			return
+		}
+	case OBJECT_LOG_LEVEL_PANIC:
+		if l, ok := logger.(PanicLogger); ok {
+			l.Panic(msg)
+			return
+		}
+	}
+	if l, ok := logger.(LevelLogger); ok {
+		l.Log(level, msg)
+		return
+	}
+	writeLevel(logger, fallbackLevel(level), msg)
+}
+
+// fallbackLevel returns the most severe of the fallback levels, which is less or equal severe than the
+// given level
+func fallbackLevel(level ObjectLogLevel) ObjectLogLevel {
+	severity := levelSeverity(level)
+	fallback := fallbackLevels[0]
+	for _, candidate := range fallbackLevels[1:] {
+		if levelSeverity(candidate) <= severity {
+			fallback = candidate
+		}
+	}
+	return fallback
+}
+
+// String returns the level as string. Implements `fmt.Stringer` and, together with `Set`, `flag.Value`.
+func (this ObjectLogLevel) String() string {
+	return string(this)
+}
+
+// Set parses the level from the given name, see `ParseLevel`. Implements `flag.Value`:
+//	level := objectlog.OBJECT_LOG_LEVEL_INFO
+//	flag.Var(&level, "log-level", "Minimum log level")
+func (this *ObjectLogLevel) Set(name string) error {
+	level, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	*this = level
+	return nil
+}
+
+// Severity returns the severity of the level. Unregistered levels have the severity of INFO.
+func (this ObjectLogLevel) Severity() int {
+	return levelSeverity(this)
+}
+
+// Compare returns -1, if the level is less severe than the other level, 1 if it is more severe and 0
+// if both are equally severe
+func (this ObjectLogLevel) Compare(other ObjectLogLevel) int {
+	a, b := this.Severity(), other.Severity()
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// Enabled returns whether the level is at least as severe as the given minimum level. All levels are
+// enabled for an empty minimum level.
+//	objectlog.OBJECT_LOG_LEVEL_WARN.Enabled(objectlog.OBJECT_LOG_LEVEL_INFO) // true
+func (this ObjectLogLevel) Enabled(min ObjectLogLevel) bool {
+	return min == "" || this.Compare(min) >= 0
+}
+
+// MarshalText implements `encoding.TextMarshaler`
+func (this ObjectLogLevel) MarshalText() ([]byte, error) {
+	return []byte(this), nil
+}
+
+// UnmarshalText implements `encoding.TextUnmarshaler`, see `ParseLevel`. Empty text results in an
+// empty level.
+func (this *ObjectLogLevel) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*this = ""
+		return nil
+	}
+	return this.Set(string(text))
+}
+
+// MarshalJSON implements `json.Marshaler`
+func (this ObjectLogLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(this))
+}
+
+// UnmarshalJSON implements `json.Unmarshaler`, see `ParseLevel`
+func (this *ObjectLogLevel) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	return this.UnmarshalText([]byte(name))
+}
@@ -0,0 +1,73 @@
+package objectlog
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type (
+
+	// LevelHandler is a `http.Handler`, which shows and changes the levels at runtime. GET responds with
+	// the current `LevelSettings` as JSON. PUT accepts the changes as JSON, see `UpdateLevels`, and
+	// responds with the resulting settings:
+	//	{
+	//		"level": "debug",
+	//		"objects": {"database": "trace", "http": ""},
+	//		"overrides": [{"pattern": "file:server.go", "level": "trace"}],
+	//		"revert": "10m"
+	//	}
+	// An empty "level" clears the global level, if omitted the global level is kept. The optional revert
+	// duration restores the levels after the given time, it defaults to the revert duration of the handler.
+	//	http.Handle("/debug/levels", objectlog.NewLevelHandler(15 * time.Minute))
+	LevelHandler struct {
+		revert time.Duration
+	}
+
+	levelHandlerRequest struct {
+		LevelSettings
+		Level  *ObjectLogLevel `json:"level"`
+		Revert string          `json:"revert"`
+	}
+)
+
+// NewLevelHandler creates new *LevelHandler with the default revert duration for changes. Zero means
+// changes are permanent, unless the request specifies a revert duration.
+func NewLevelHandler(revert time.Duration) *LevelHandler {
+	return &LevelHandler{
+		revert: revert,
+	}
+}
+
+// ServeHTTP implements `http.Handler`
+func (this *LevelHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case "GET", "HEAD":
+	case "PUT":
+		update := &levelHandlerRequest{}
+		if err := json.NewDecoder(req.Body).Decode(update); err != nil {
+			http.Error(rw, "invalid levels: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		revert := this.revert
+		if update.Revert != "" {
+			var err error
+			if revert, err = time.ParseDuration(update.Revert); err != nil {
+				http.Error(rw, "invalid revert: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		levelState.update(revert, func(settings *LevelSettings) {
+			settings.apply(&update.LevelSettings)
+			if update.Level != nil {
+				settings.Level = *update.Level
+			}
+		})
+	default:
+		rw.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(CurrentLevels())
+}
@@ -0,0 +1,77 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testLevelRequest(t *testing.T, url, method, body string) (int, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	assert.Nil(t, err)
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+	raw, err := ioutil.ReadAll(res.Body)
+	assert.Nil(t, err)
+	return res.StatusCode, string(raw)
+}
+
+func TestLevelHandler(t *testing.T) {
+	defer resetRuntimeLevels()
+	server := httptest.NewServer(NewLevelHandler(0))
+	defer server.Close()
+
+	code, body := testLevelRequest(t, server.URL, "GET", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"level":"","objects":{},"overrides":[]}`+"\n", body)
+
+	code, body = testLevelRequest(t, server.URL, "PUT", `{"level":"DEBUG","objects":{"db":"warning"}}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"level":"debug","objects":{"db":"warn"},"overrides":[]}`+"\n", body)
+	assert.Equal(t, OBJECT_LOG_LEVEL_DEBUG, GlobalLevel())
+
+	code, body = testLevelRequest(t, server.URL, "PUT", `{"objects":{"db":""},"revert":"1h"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"level":"debug","objects":{},"overrides":[]}`+"\n", body)
+	RevertLevels()
+	assert.Equal(t, map[string]ObjectLogLevel{"db": OBJECT_LOG_LEVEL_WARN}, CurrentLevels().Objects)
+
+	code, body = testLevelRequest(t, server.URL, "PUT", `{"level":""}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `{"level":"","objects":{"db":"warn"},"overrides":[]}`+"\n", body)
+}
+
+func TestLevelHandler_Revert(t *testing.T) {
+	defer resetRuntimeLevels()
+	server := httptest.NewServer(NewLevelHandler(20 * time.Millisecond))
+	defer server.Close()
+
+	code, _ := testLevelRequest(t, server.URL, "PUT", `{"level":"trace"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, OBJECT_LOG_LEVEL_TRACE, GlobalLevel())
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, ObjectLogLevel(""), GlobalLevel())
+}
+
+func TestLevelHandler_Errors(t *testing.T) {
+	defer resetRuntimeLevels()
+	server := httptest.NewServer(NewLevelHandler(0))
+	defer server.Close()
+
+	code, body := testLevelRequest(t, server.URL, "PUT", `{"level":"nope"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, `invalid levels: objectlog: unknown level "nope"`+"\n", body)
+
+	code, body = testLevelRequest(t, server.URL, "PUT", `{"level":"info","revert":"soon"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "invalid revert: ")
+	assert.Equal(t, ObjectLogLevel(""), GlobalLevel())
+
+	code, _ = testLevelRequest(t, server.URL, "DELETE", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
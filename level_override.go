@@ -0,0 +1,270 @@
+package objectlog
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type (
+
+	// LevelOverride sets the minimum level for all `ObjectLog` instances matching a glob pattern (see
+	// `path.Match`), like glog's "-vmodule". By default, the pattern is matched against the name, the path
+	// (names joined with "/") and the prefix of the `ObjectLog`. Patterns starting with "file:" are matched
+	// against the source file of the caller, e.g. "file:server.go" or "file:http/*.go", and patterns
+	// starting with "pkg:" against the package path of the caller, e.g. "pkg:*/database".
+	LevelOverride struct {
+		Pattern string         `json:"pattern"`
+		Level   ObjectLogLevel `json:"level"`
+	}
+
+	// LevelOverrides is a list of overrides, of which the first matching applies. It implements
+	// `flag.Value`, using comma separated `<pattern>=<level>` pairs:
+	//	var overrides objectlog.LevelOverrides
+	//	flag.Var(&overrides, "vmodule", "Level overrides")
+	//	// -vmodule "database/*=debug,file:server.go=trace"
+	LevelOverrides []LevelOverride
+
+	// levelCache caches the level override of an `ObjectLog` for one generation of level settings
+	levelCache struct {
+		generation uint64
+		level      ObjectLogLevel
+		ok         bool
+	}
+)
+
+const (
+	levelOverrideFile = "file:"
+	levelOverridePkg  = "pkg:"
+)
+
+var (
+
+	// callerOverrides is the number of overrides, which match the caller
+	callerOverrides int32
+
+	// callerLevelCache caches the level overrides by program counter of the caller
+	callerLevelCache = &sync.Map{}
+
+	// packageDir is the directory of this package, to skip internal frames when looking up the caller
+	packageDir = func() string {
+		_, file, _, _ := runtime.Caller(0)
+		return filepath.Dir(file)
+	}()
+)
+
+// ParseLevelOverrides parses comma separated `<pattern>=<level>` pairs
+//	overrides, err := objectlog.ParseLevelOverrides("database/*=debug,file:server.go=trace")
+func ParseLevelOverrides(spec string) (LevelOverrides, error) {
+	overrides := LevelOverrides{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		idx := strings.LastIndex(part, "=")
+		if idx < 1 {
+			return nil, fmt.Errorf("objectlog: invalid level override %q, expected <pattern>=<level>", part)
+		}
+		level, err := ParseLevel(part[idx+1:])
+		if err != nil {
+			return nil, err
+		}
+		override := LevelOverride{Pattern: part[:idx], Level: level}
+		if _, err := path.Match(override.pattern(), ""); err != nil {
+			return nil, fmt.Errorf("objectlog: invalid level override pattern %q: %s", override.Pattern, err)
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, nil
+}
+
+// SetLevelOverrides replaces all level overrides. Pending reverts are cancelled.
+//	objectlog.SetLevelOverrides(objectlog.LevelOverrides{{"database/*", objectlog.OBJECT_LOG_LEVEL_DEBUG}})
+func SetLevelOverrides(overrides LevelOverrides) {
+	if overrides == nil {
+		overrides = LevelOverrides{}
+	}
+	UpdateLevels(&LevelSettings{Overrides: overrides}, 0)
+}
+
+// String returns the overrides as comma separated `<pattern>=<level>` pairs
+func (this LevelOverrides) String() string {
+	parts := make([]string, len(this))
+	for i, override := range this {
+		parts[i] = override.Pattern + "=" + string(override.Level)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set parses the overrides, see `ParseLevelOverrides`. Implements `flag.Value`.
+func (this *LevelOverrides) Set(spec string) error {
+	overrides, err := ParseLevelOverrides(spec)
+	if err != nil {
+		return err
+	}
+	*this = overrides
+	return nil
+}
+
+// isCaller returns whether the override matches the caller, instead of the `ObjectLog`
+func (this LevelOverride) isCaller() bool {
+	return strings.HasPrefix(this.Pattern, levelOverrideFile) || strings.HasPrefix(this.Pattern, levelOverridePkg)
+}
+
+// pattern returns the glob pattern without the "file:" or "pkg:" prefix
+func (this LevelOverride) pattern() string {
+	return strings.TrimPrefix(strings.TrimPrefix(this.Pattern, levelOverrideFile), levelOverridePkg)
+}
+
+// matchObject returns whether the override matches the name, path or prefix of the `ObjectLog`
+func (this LevelOverride) matchObject(log *ObjectLog) bool {
+	if this.isCaller() {
+		return false
+	}
+	for _, candidate := range []string{log.name, strings.Join(log.LogPath(), "/"), log.prefix} {
+		if candidate == "" {
+			continue
+		}
+		if ok, _ := path.Match(this.Pattern, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCaller returns whether the override matches the file or package of the caller. Patterns without
+// a slash are matched against the base name, others against the same number of trailing path elements.
+func (this LevelOverride) matchCaller(file, pkg string) bool {
+	switch {
+	case strings.HasPrefix(this.Pattern, levelOverrideFile):
+		return matchTrailingPath(this.pattern(), filepath.ToSlash(file))
+	case strings.HasPrefix(this.Pattern, levelOverridePkg):
+		return matchTrailingPath(this.pattern(), pkg)
+	}
+	return false
+}
+
+func matchTrailingPath(pattern, name string) bool {
+	parts := strings.Split(name, "/")
+	if count := strings.Count(pattern, "/") + 1; count < len(parts) {
+		parts = parts[len(parts)-count:]
+	}
+	ok, _ := path.Match(pattern, strings.Join(parts, "/"))
+	return ok
+}
+
+// objectLevel returns the runtime level of the `ObjectLog`, from the object levels or the overrides.
+// Object related results are cached per `ObjectLog`, caller related results per caller.
+func objectLevel(log *ObjectLog) (ObjectLogLevel, bool) {
+	generation := atomic.LoadUint64(&levelGeneration)
+	if generation == 0 {
+		return "", false
+	}
+	var cache *levelCache
+	if log.override != nil {
+		cache, _ = log.override.Load().(*levelCache)
+	}
+	if cache == nil || cache.generation != generation {
+		cache = &levelCache{generation: generation}
+		cache.level, cache.ok = lookupObjectLevel(log)
+		if log.override != nil {
+			log.override.Store(cache)
+		}
+	}
+	if cache.ok {
+		return cache.level, true
+	}
+	return callerLevel(generation)
+}
+
+// lookupObjectLevel returns the level of the first object level or override matching the `ObjectLog`
+func lookupObjectLevel(log *ObjectLog) (ObjectLogLevel, bool) {
+	levelState.RLock()
+	defer levelState.RUnlock()
+	for _, key := range []string{log.name, strings.Join(log.LogPath(), "/"), log.prefix} {
+		if level, ok := levelState.settings.Objects[key]; ok && key != "" {
+			return level, true
+		}
+	}
+	for _, override := range levelState.settings.Overrides {
+		if override.matchObject(log) {
+			return override.Level, true
+		}
+	}
+	return "", false
+}
+
+// callerLevel returns the level of the first override matching the caller outside of this package
+func callerLevel(generation uint64) (ObjectLogLevel, bool) {
+	if atomic.LoadInt32(&callerOverrides) == 0 {
+		return "", false
+	}
+	pcs := make([]uintptr, 16)
+	if frame, ok := externalFrame(pcs[:runtime.Callers(3, pcs)]); ok {
+		return callerLevelByFrame(generation, frame)
+	}
+	return "", false
+}
+
+// externalFrame returns the first frame of the program counters outside of this package
+func externalFrame(pcs []uintptr) (runtime.Frame, bool) {
+	if len(pcs) == 0 {
+		return runtime.Frame{}, false
+	}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != packageDir || strings.HasSuffix(frame.File, "_test.go") {
+			return frame, true
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+func callerLevelByFrame(generation uint64, frame runtime.Frame) (ObjectLogLevel, bool) {
+	if cached, ok := callerLevelCache.Load(frame.PC); ok {
+		if cache := cached.(*levelCache); cache.generation == generation {
+			return cache.level, cache.ok
+		}
+	}
+	cache := &levelCache{generation: generation}
+	pkg := framePackage(frame.Function)
+	levelState.RLock()
+	for _, override := range levelState.settings.Overrides {
+		if override.matchCaller(frame.File, pkg) {
+			cache.level, cache.ok = override.Level, true
+			break
+		}
+	}
+	levelState.RUnlock()
+	callerLevelCache.Store(frame.PC, cache)
+	return cache.level, cache.ok
+}
+
+// framePackage returns the package path from a fully qualified function name, e.g.
+// "github.com/foo/bar.(*Baz).Method" -> "github.com/foo/bar"
+func framePackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
+
+// countCallerOverrides returns the number of overrides, which match the caller
+func countCallerOverrides(overrides LevelOverrides) int32 {
+	count := int32(0)
+	for _, override := range overrides {
+		if override.isCaller() {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,97 @@
+package objectlog
+
+import (
+	"flag"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestParseLevelOverrides(t *testing.T) {
+	overrides, err := ParseLevelOverrides(" db/*=debug, file:server.go=TRACE,,pkg:*/http=warning ")
+	assert.Nil(t, err)
+	assert.Equal(t, LevelOverrides{
+		{"db/*", OBJECT_LOG_LEVEL_DEBUG},
+		{"file:server.go", OBJECT_LOG_LEVEL_TRACE},
+		{"pkg:*/http", OBJECT_LOG_LEVEL_WARN},
+	}, overrides)
+	assert.Equal(t, "db/*=debug,file:server.go=trace,pkg:*/http=warn", overrides.String())
+
+	_, err = ParseLevelOverrides("db")
+	assert.EqualError(t, err, `objectlog: invalid level override "db", expected <pattern>=<level>`)
+	_, err = ParseLevelOverrides("db=nope")
+	assert.EqualError(t, err, `objectlog: unknown level "nope"`)
+	_, err = ParseLevelOverrides("file:[=debug")
+	assert.EqualError(t, err, `objectlog: invalid level override pattern "file:[": syntax error in pattern`)
+}
+
+func TestLevelOverrides_Flag(t *testing.T) {
+	var overrides LevelOverrides
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.Var(&overrides, "vmodule", "")
+	assert.Nil(t, flags.Parse([]string{"-vmodule", "db*=debug"}))
+	assert.Equal(t, LevelOverrides{{"db*", OBJECT_LOG_LEVEL_DEBUG}}, overrides)
+}
+
+func TestSetLevelOverrides_Object(t *testing.T) {
+	defer resetRuntimeLevels()
+	lg := NewBufferObjectLog()
+	db := NewObjectLog(lg).SetLogName("db").SetLogLevel(OBJECT_LOG_LEVEL_ERROR)
+	pool := db.LogChild("pool", nil)
+	web := NewObjectLog(lg).SetLogPrefix("[web] ").SetLogLevel(OBJECT_LOG_LEVEL_ERROR)
+
+	SetLevelOverrides(LevelOverrides{
+		{"db/*", OBJECT_LOG_LEVEL_DEBUG},
+		{"db", OBJECT_LOG_LEVEL_INFO},
+		{"\\[web]*", OBJECT_LOG_LEVEL_WARN},
+	})
+	db.LogDebug("db hidden")
+	db.LogInfo("db")
+	pool.LogDebug("pool")
+	web.LogInfo("web hidden")
+	web.LogWarn("web")
+
+	// cache is invalidated on change of the name
+	pool.SetLogName("")
+	pool.LogDebug("other hidden")
+	pool.LogInfo("other")
+
+	// cache is invalidated on change of the overrides
+	SetLevelOverrides(nil)
+	db.LogInfo("db hidden")
+	assert.Equal(t, strings.Join([]string{
+		"[INF] db",
+		"[DBG] pool: pool",
+		"[WRN] [web] web",
+		"[INF] pool: other",
+	}, "\n")+"\n", lg.Buffer().String())
+}
+
+func TestSetLevelOverrides_Caller(t *testing.T) {
+	defer resetRuntimeLevels()
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogLevel(OBJECT_LOG_LEVEL_ERROR)
+
+	SetLevelOverrides(LevelOverrides{{"file:level_override_test.go", OBJECT_LOG_LEVEL_DEBUG}})
+	ol.LogDebug("file")
+	SetLevelOverrides(LevelOverrides{{"file:other.go", OBJECT_LOG_LEVEL_DEBUG}})
+	ol.LogDebug("file hidden")
+	SetLevelOverrides(LevelOverrides{{"pkg:*/objectlog", OBJECT_LOG_LEVEL_INFO}})
+	ol.LogWith("foo", "bar").LogInfo("pkg")
+	ol.LogDebug("pkg hidden")
+	assert.Equal(t, strings.Join([]string{
+		"[DBG] file",
+		`[INF] pkg :: {"foo":"bar"}`,
+	}, "\n")+"\n", lg.Buffer().String())
+}
+
+func TestMatchTrailingPath(t *testing.T) {
+	assert.True(t, matchTrailingPath("server.go", "/src/app/http/server.go"))
+	assert.True(t, matchTrailingPath("http/*.go", "/src/app/http/server.go"))
+	assert.False(t, matchTrailingPath("app/*.go", "/src/app/http/server.go"))
+	assert.True(t, matchTrailingPath("*/http", "github.com/foo/http"))
+	assert.Equal(t, "github.com/foo/bar", framePackage("github.com/foo/bar.(*Baz).Method"))
+	assert.Equal(t, "main", framePackage("main.main"))
+}
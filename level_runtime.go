@@ -0,0 +1,188 @@
+package objectlog
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+
+	// LevelSettings describes the levels, which can be changed at runtime. The global level is used by
+	// all `ObjectLog` instances without an own level. The object levels override the level of `ObjectLog`
+	// instances whose name, path (names joined with "/") or prefix equals the key. The overrides are
+	// matched with glob patterns, if no object level applies. See `LevelOverride`.
+	LevelSettings struct {
+		Level     ObjectLogLevel            `json:"level"`
+		Objects   map[string]ObjectLogLevel `json:"objects"`
+		Overrides LevelOverrides            `json:"overrides"`
+	}
+
+	runtimeLevels struct {
+		sync.RWMutex
+		settings *LevelSettings
+		revertTo *LevelSettings
+		timer    *time.Timer
+		token    uint64
+	}
+)
+
+var (
+
+	// levelGeneration is incremented on every change of the level settings, to invalidate cached levels
+	levelGeneration uint64
+
+	levelState = &runtimeLevels{
+		settings: &LevelSettings{Objects: map[string]ObjectLogLevel{}},
+	}
+
+	// verboseLevel holds the `ObjectLogLevel` set with `SetVerboseLevel`
+	verboseLevel atomic.Value
+)
+
+// GlobalLevel returns the global minimum level (can be empty string)
+func GlobalLevel() ObjectLogLevel {
+	levelState.RLock()
+	defer levelState.RUnlock()
+	return levelState.settings.Level
+}
+
+// SetGlobalLevel sets the global minimum level, which is used by all `ObjectLog` instances which have
+// no own level. An empty level writes all messages. Pending reverts are cancelled.
+func SetGlobalLevel(level ObjectLogLevel) {
+	levelState.update(0, func(settings *LevelSettings) {
+		settings.Level = level
+	})
+}
+
+// VerboseLevel returns the level set with `SetVerboseLevel` (can be empty string)
+func VerboseLevel() ObjectLogLevel {
+	level, _ := verboseLevel.Load().(ObjectLogLevel)
+	return level
+}
+
+// SetVerboseLevel enables messages of at least the given level for all `ObjectLog` instances, in addition
+// to those enabled by their own, object or global level. It can only increase verbosity, an empty level
+// disables it. It is independent of the other runtime levels and used by `NotifyLevelSignals`.
+func SetVerboseLevel(level ObjectLogLevel) {
+	verboseLevel.Store(level)
+}
+
+// SetObjectLevel sets the level for all `ObjectLog` instances whose name, path or prefix equals the key.
+// It takes precedence over the own level of the `ObjectLog`. An empty level removes the override.
+// Pending reverts are cancelled.
+//	objectlog.SetObjectLevel("database", objectlog.OBJECT_LOG_LEVEL_DEBUG)
+func SetObjectLevel(key string, level ObjectLogLevel) {
+	UpdateLevels(&LevelSettings{Objects: map[string]ObjectLogLevel{key: level}}, 0)
+}
+
+// CurrentLevels returns a copy of the current level settings
+func CurrentLevels() *LevelSettings {
+	levelState.RLock()
+	defer levelState.RUnlock()
+	return levelState.settings.copy()
+}
+
+// UpdateLevels changes the global level, if not empty, and the provided object levels. Object levels
+// with an empty level are removed. The overrides are replaced, if not nil. If revert is positive, then
+// all levels are restored after the given duration to the state before the first of all not yet
+// reverted changes. Otherwise pending reverts are cancelled.
+//	// turn on debug for database for 10 minutes
+//	objectlog.UpdateLevels(&objectlog.LevelSettings{
+//		Objects: map[string]objectlog.ObjectLogLevel{"database": objectlog.OBJECT_LOG_LEVEL_DEBUG},
+//	}, 10*time.Minute)
+func UpdateLevels(update *LevelSettings, revert time.Duration) {
+	levelState.update(revert, func(settings *LevelSettings) {
+		settings.apply(update)
+	})
+}
+
+// RevertLevels immediately restores the levels, which have been changed temporarily with `UpdateLevels`.
+// It does nothing, if there is no pending revert.
+func RevertLevels() {
+	levelState.Lock()
+	defer levelState.Unlock()
+	levelState.revert()
+}
+
+// update modifies a copy of the current settings, which then replaces them. If revert is positive, then
+// a revert is scheduled, otherwise a pending revert is cancelled.
+func (this *runtimeLevels) update(revert time.Duration, modify func(settings *LevelSettings)) {
+	this.Lock()
+	defer this.Unlock()
+	settings := this.settings.copy()
+	modify(settings)
+	this.stopRevert(revert > 0)
+	if revert > 0 {
+		if this.revertTo == nil {
+			this.revertTo = this.settings
+		}
+		token := this.token
+		this.timer = time.AfterFunc(revert, func() {
+			this.Lock()
+			defer this.Unlock()
+			// a newer change may have replaced the revert, while waiting for the lock
+			if this.token == token {
+				this.revert()
+			}
+		})
+	}
+	this.settings = settings
+	this.changed()
+}
+
+// revert restores the settings from before the temporary changes, if any
+func (this *runtimeLevels) revert() {
+	if this.revertTo != nil {
+		this.settings = this.revertTo
+		this.changed()
+	}
+	this.stopRevert(false)
+}
+
+// changed invalidates all cached levels after the settings have been replaced
+func (this *runtimeLevels) changed() {
+	atomic.StoreInt32(&callerOverrides, countCallerOverrides(this.settings.Overrides))
+	atomic.AddUint64(&levelGeneration, 1)
+}
+
+// stopRevert cancels a pending revert. The state to revert to is kept, if requested.
+func (this *runtimeLevels) stopRevert(keep bool) {
+	this.token++
+	if this.timer != nil {
+		this.timer.Stop()
+		this.timer = nil
+	}
+	if !keep {
+		this.revertTo = nil
+	}
+}
+
+// apply changes the settings like described by `UpdateLevels`
+func (this *LevelSettings) apply(update *LevelSettings) {
+	if update.Level != "" {
+		this.Level = update.Level
+	}
+	for key, level := range update.Objects {
+		if level == "" {
+			delete(this.Objects, key)
+		} else {
+			this.Objects[key] = level
+		}
+	}
+	if update.Overrides != nil {
+		this.Overrides = append(LevelOverrides{}, update.Overrides...)
+	}
+}
+
+func (this *LevelSettings) copy() *LevelSettings {
+	settings := &LevelSettings{
+		Level:     this.Level,
+		Objects:   make(map[string]ObjectLogLevel, len(this.Objects)),
+		Overrides: append(LevelOverrides{}, this.Overrides...),
+	}
+	for key, level := range this.Objects {
+		settings.Objects[key] = level
+	}
+	return settings
+}
@@ -0,0 +1,127 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resetRuntimeLevels restores the initial runtime level state after a test
+func resetRuntimeLevels() {
+	RevertLevels()
+	SetVerboseLevel("")
+	levelState.Lock()
+	levelState.settings = &LevelSettings{Objects: map[string]ObjectLogLevel{}}
+	levelState.changed()
+	levelState.Unlock()
+}
+
+func TestGlobalLevel(t *testing.T) {
+	defer resetRuntimeLevels()
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg)
+	own := NewObjectLog(lg).SetLogLevel(OBJECT_LOG_LEVEL_DEBUG)
+
+	SetGlobalLevel(OBJECT_LOG_LEVEL_WARN)
+	assert.Equal(t, OBJECT_LOG_LEVEL_WARN, GlobalLevel())
+	ol.LogInfo("hidden")
+	ol.LogWarn("global")
+	own.LogDebug("own")
+	assert.Equal(t, strings.Join([]string{
+		"[WRN] global",
+		"[DBG] own",
+	}, "\n")+"\n", lg.Buffer().String())
+}
+
+func TestVerboseLevel(t *testing.T) {
+	defer resetRuntimeLevels()
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogLevel(OBJECT_LOG_LEVEL_ERROR)
+	SetGlobalLevel(OBJECT_LOG_LEVEL_WARN)
+
+	SetVerboseLevel(OBJECT_LOG_LEVEL_DEBUG)
+	assert.Equal(t, OBJECT_LOG_LEVEL_DEBUG, VerboseLevel())
+	ol.LogTrace("hidden")
+	ol.LogDebug("verbose")
+	NewObjectLog(lg).LogInfo("global")
+	SetVerboseLevel("")
+	ol.LogWarn("hidden")
+	assert.Equal(t, strings.Join([]string{
+		"[DBG] verbose",
+		"[INF] global",
+	}, "\n")+"\n", lg.Buffer().String())
+	assert.Equal(t, OBJECT_LOG_LEVEL_WARN, GlobalLevel())
+}
+
+func TestObjectLevel(t *testing.T) {
+	defer resetRuntimeLevels()
+	lg := NewBufferObjectLog()
+	parent := NewObjectLog(lg).SetLogName("db").SetLogLevel(OBJECT_LOG_LEVEL_ERROR)
+	child := parent.LogChild("pool", nil)
+	other := NewObjectLog(lg).SetLogPrefix("other: ").SetLogLevel(OBJECT_LOG_LEVEL_ERROR)
+
+	SetObjectLevel("db/pool", OBJECT_LOG_LEVEL_DEBUG)
+	SetObjectLevel("other: ", OBJECT_LOG_LEVEL_INFO)
+	assert.Equal(t, map[string]ObjectLogLevel{
+		"db/pool": OBJECT_LOG_LEVEL_DEBUG,
+		"other: ": OBJECT_LOG_LEVEL_INFO,
+	}, CurrentLevels().Objects)
+	parent.LogDebug("parent hidden")
+	child.LogDebug("child")
+	other.LogDebug("other hidden")
+	other.LogInfo("other")
+
+	SetObjectLevel("db/pool", "")
+	child.LogDebug("child hidden")
+	assert.Equal(t, strings.Join([]string{
+		"[DBG] pool: child",
+		"[INF] other: other",
+	}, "\n")+"\n", lg.Buffer().String())
+}
+
+func TestUpdateLevels_Revert(t *testing.T) {
+	defer resetRuntimeLevels()
+	SetGlobalLevel(OBJECT_LOG_LEVEL_INFO)
+	UpdateLevels(&LevelSettings{Level: OBJECT_LOG_LEVEL_DEBUG}, time.Hour)
+	UpdateLevels(&LevelSettings{
+		Level:   OBJECT_LOG_LEVEL_TRACE,
+		Objects: map[string]ObjectLogLevel{"db": OBJECT_LOG_LEVEL_TRACE},
+	}, 20*time.Millisecond)
+	assert.Equal(t, OBJECT_LOG_LEVEL_TRACE, GlobalLevel())
+	assert.Equal(t, map[string]ObjectLogLevel{"db": OBJECT_LOG_LEVEL_TRACE}, CurrentLevels().Objects)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, OBJECT_LOG_LEVEL_INFO, GlobalLevel())
+	assert.Equal(t, map[string]ObjectLogLevel{}, CurrentLevels().Objects)
+}
+
+func TestRevertLevels(t *testing.T) {
+	defer resetRuntimeLevels()
+	UpdateLevels(&LevelSettings{Level: OBJECT_LOG_LEVEL_DEBUG}, time.Hour)
+	RevertLevels()
+	assert.Equal(t, ObjectLogLevel(""), GlobalLevel())
+
+	UpdateLevels(&LevelSettings{Level: OBJECT_LOG_LEVEL_DEBUG}, time.Hour)
+	SetGlobalLevel(OBJECT_LOG_LEVEL_WARN)
+	RevertLevels()
+	assert.Equal(t, OBJECT_LOG_LEVEL_WARN, GlobalLevel())
+}
+
+func TestUpdateLevels_StaleRevert(t *testing.T) {
+	defer resetRuntimeLevels()
+	UpdateLevels(&LevelSettings{Level: OBJECT_LOG_LEVEL_DEBUG}, time.Millisecond)
+
+	// the timer fires while a newer update holds the lock
+	levelState.Lock()
+	time.Sleep(50 * time.Millisecond)
+	levelState.stopRevert(true)
+	levelState.settings = &LevelSettings{Level: OBJECT_LOG_LEVEL_TRACE, Objects: map[string]ObjectLogLevel{}}
+	levelState.changed()
+	levelState.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, OBJECT_LOG_LEVEL_TRACE, GlobalLevel(), "stale revert is ignored")
+	RevertLevels()
+	assert.Equal(t, ObjectLogLevel(""), GlobalLevel())
+}
@@ -0,0 +1,104 @@
+package objectlog
+
+import (
+	"os"
+	"os/signal"
+	"time"
+)
+
+var (
+
+	// DefaultSignalLevels are the verbose levels, which are cycled through by `NotifyLevelSignals`, if no
+	// levels are provided
+	DefaultSignalLevels = []ObjectLogLevel{
+		OBJECT_LOG_LEVEL_INFO,
+		OBJECT_LOG_LEVEL_DEBUG,
+		OBJECT_LOG_LEVEL_TRACE,
+	}
+)
+
+// NotifyLevelSignals changes the verbose level on signals, see `SetVerboseLevel`, so that all objects
+// become more verbose, including those with an own level: The cycle signal sets the next of the given
+// levels, starting over after the last. The reset signal restores the verbose level from before the
+// first cycle. If revert is positive, then the verbose level is restored automatically after the given
+// duration since the last cycle. Other runtime levels, like those changed with `UpdateLevels`, are not
+// affected. The returned function stops the signal handling.
+//	stop := objectlog.NotifyLevelSignals(syscall.SIGUSR1, syscall.SIGUSR2, 10*time.Minute)
+//	defer stop()
+func NotifyLevelSignals(cycle, reset os.Signal, revert time.Duration, levels ...ObjectLogLevel) func() {
+	if len(levels) == 0 {
+		levels = DefaultSignalLevels
+	}
+	signals := make(chan os.Signal, 1)
+	reverts := make(chan uint64, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, cycle, reset)
+	go func() {
+		var (
+			initial ObjectLogLevel
+			cycling bool
+			timer   *time.Timer
+			token   uint64
+		)
+		restore := func() {
+			if timer != nil {
+				timer.Stop()
+			}
+			token++
+			if cycling {
+				SetVerboseLevel(initial)
+				cycling = false
+			}
+		}
+		for {
+			select {
+			case sig := <-signals:
+				if sig == reset {
+					restore()
+					continue
+				}
+				if !cycling {
+					initial, cycling = VerboseLevel(), true
+				}
+				SetVerboseLevel(nextSignalLevel(VerboseLevel(), levels))
+				if revert > 0 {
+					if timer != nil {
+						timer.Stop()
+					}
+					token++
+					current := token
+					timer = time.AfterFunc(revert, func() {
+						select {
+						case reverts <- current:
+						case <-done:
+						}
+					})
+				}
+			case expired := <-reverts:
+				// ignore timers, which fired before being replaced
+				if expired == token {
+					restore()
+				}
+			case <-done:
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// nextSignalLevel returns the level following the current level in the list of levels, or the first
+func nextSignalLevel(current ObjectLogLevel, levels []ObjectLogLevel) ObjectLogLevel {
+	for i, level := range levels {
+		if level == current {
+			return levels[(i+1)%len(levels)]
+		}
+	}
+	return levels[0]
+}
@@ -0,0 +1,13 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNextSignalLevel(t *testing.T) {
+	assert.Equal(t, OBJECT_LOG_LEVEL_INFO, nextSignalLevel("", DefaultSignalLevels))
+	assert.Equal(t, OBJECT_LOG_LEVEL_DEBUG, nextSignalLevel(OBJECT_LOG_LEVEL_INFO, DefaultSignalLevels))
+	assert.Equal(t, OBJECT_LOG_LEVEL_TRACE, nextSignalLevel(OBJECT_LOG_LEVEL_DEBUG, DefaultSignalLevels))
+	assert.Equal(t, OBJECT_LOG_LEVEL_INFO, nextSignalLevel(OBJECT_LOG_LEVEL_TRACE, DefaultSignalLevels))
+}
@@ -0,0 +1,18 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package objectlog
+
+import (
+	"syscall"
+	"time"
+)
+
+// NotifyDefaultLevelSignals calls `NotifyLevelSignals` with SIGUSR1 to cycle through the
+// `DefaultSignalLevels` and SIGUSR2 to reset.
+//	kill -USR1 <pid> # verbose level info
+//	kill -USR1 <pid> # verbose level debug
+//	kill -USR2 <pid> # reset
+func NotifyDefaultLevelSignals(revert time.Duration) func() {
+	return NotifyLevelSignals(syscall.SIGUSR1, syscall.SIGUSR2, revert)
+}
@@ -0,0 +1,52 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func waitForVerboseLevel(level ObjectLogLevel) ObjectLogLevel {
+	for i := 0; i < 100 && VerboseLevel() != level; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return VerboseLevel()
+}
+
+func TestNotifyDefaultLevelSignals(t *testing.T) {
+	defer resetRuntimeLevels()
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogLevel(OBJECT_LOG_LEVEL_WARN)
+	SetGlobalLevel(OBJECT_LOG_LEVEL_ERROR)
+	stop := NotifyDefaultLevelSignals(time.Hour)
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	assert.Equal(t, OBJECT_LOG_LEVEL_INFO, waitForVerboseLevel(OBJECT_LOG_LEVEL_INFO))
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	assert.Equal(t, OBJECT_LOG_LEVEL_DEBUG, waitForVerboseLevel(OBJECT_LOG_LEVEL_DEBUG))
+	ol.LogDebug("own level raised")
+
+	UpdateLevels(&LevelSettings{Objects: map[string]ObjectLogLevel{"db": OBJECT_LOG_LEVEL_TRACE}}, time.Hour)
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	assert.Equal(t, ObjectLogLevel(""), waitForVerboseLevel(""))
+	ol.LogDebug("hidden")
+	assert.Equal(t, "[DBG] own level raised\n", lg.Buffer().String())
+	assert.Equal(t, OBJECT_LOG_LEVEL_ERROR, GlobalLevel())
+	assert.Equal(t, map[string]ObjectLogLevel{"db": OBJECT_LOG_LEVEL_TRACE}, CurrentLevels().Objects, "pending changes are kept")
+}
+
+func TestNotifyLevelSignals_Revert(t *testing.T) {
+	defer resetRuntimeLevels()
+	SetVerboseLevel(OBJECT_LOG_LEVEL_WARN)
+	stop := NotifyLevelSignals(syscall.SIGUSR1, syscall.SIGUSR2, 50*time.Millisecond, OBJECT_LOG_LEVEL_DEBUG, OBJECT_LOG_LEVEL_TRACE)
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	assert.Equal(t, OBJECT_LOG_LEVEL_DEBUG, waitForVerboseLevel(OBJECT_LOG_LEVEL_DEBUG))
+	assert.Equal(t, OBJECT_LOG_LEVEL_WARN, waitForVerboseLevel(OBJECT_LOG_LEVEL_WARN), "restored from before the first cycle")
+}
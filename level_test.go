@@ -0,0 +1,192 @@
+package objectlog
+
+import (
+	"encoding/json"
+	"flag"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+type (
+	testBasicLogger struct {
+		lines []string
+	}
+)
+
+func (this *testBasicLogger) Debug(msg string) { this.lines = append(this.lines, "debug: "+msg) }
+func (this *testBasicLogger) Info(msg string)  { this.lines = append(this.lines, "info: "+msg) }
+func (this *testBasicLogger) Warn(msg string)  { this.lines = append(this.lines, "warn: "+msg) }
+func (this *testBasicLogger) Error(msg string) { this.lines = append(this.lines, "error: "+msg) }
+func (this *testBasicLogger) Fatal(msg string) { this.lines = append(this.lines, "fatal: "+msg) }
+
+const (
+	testLevelAudit ObjectLogLevel = "test-audit"
+	testLevelLoud  ObjectLogLevel = "test-loud"
+)
+
+func init() {
+	RegisterLevel(testLevelAudit, 45, "AUDIT")
+	RegisterLevel(testLevelLoud, 100, "LOUD")
+}
+
+func TestLevels(t *testing.T) {
+	severity, ok := LevelSeverity(testLevelAudit)
+	assert.True(t, ok)
+	assert.Equal(t, 45, severity)
+	_, ok = LevelSeverity("unknown")
+	assert.False(t, ok)
+	assert.Equal(t, "AUDIT", LevelName(testLevelAudit))
+	assert.Equal(t, "NOTICE", LevelName(OBJECT_LOG_LEVEL_NOTICE))
+	assert.Equal(t, "UNKNOWN", LevelName("unknown"))
+	assert.Equal(t, []ObjectLogLevel{
+		OBJECT_LOG_LEVEL_TRACE,
+		OBJECT_LOG_LEVEL_DEBUG,
+		OBJECT_LOG_LEVEL_INFO,
+		OBJECT_LOG_LEVEL_NOTICE,
+		OBJECT_LOG_LEVEL_WARN,
+		testLevelAudit,
+		OBJECT_LOG_LEVEL_ERROR,
+		OBJECT_LOG_LEVEL_PANIC,
+		OBJECT_LOG_LEVEL_FATAL,
+		testLevelLoud,
+	}, Levels())
+}
+
+func TestObjectLog_LogFatal_AboveLevel(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogLevel(testLevelLoud)
+	ol.LogError("hidden")
+	ol.LogFatal("fatal")
+	assert.Equal(t, "[FTL] fatal\n", lg.Buffer().String())
+}
+
+func TestObjectLog_Log_Fallback(t *testing.T) {
+	lg := &testBasicLogger{}
+	ol := NewObjectLog(lg)
+	ol.LogTrace("trace")
+	ol.LogNotice("notice")
+	ol.Log(testLevelAudit, "audit")
+	ol.Log(testLevelLoud, "loud")
+	ol.Log("unknown", "unknown")
+	assert.Panics(t, func() {
+		ol.LogPanic("panic %d", 1)
+	})
+	assert.Equal(t, []string{
+		"debug: trace",
+		"info: notice",
+		"warn: audit",
+		"error: loud",
+		"info: unknown",
+		"error: panic 1",
+	}, lg.lines)
+}
+
+func TestObjectLog_Log_Levels(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg)
+	ol.LogTrace("trace")
+	ol.LogNotice("notice")
+	ol.Log(testLevelAudit, "audit")
+	ol.LogWith("foo", "bar").Log(OBJECT_LOG_LEVEL_INFO, "info")
+	assert.Equal(t, strings.Join([]string{
+		"[TRC] trace",
+		"[NTC] notice",
+		"[AUDIT] audit",
+		`[INF] info :: {"foo":"bar"}`,
+	}, "\n")+"\n", lg.Buffer().String())
+}
+
+func TestObjectLog_LogPanic(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogLevel(OBJECT_LOG_LEVEL_FATAL)
+	assert.Panics(t, func() {
+		ol.LogPanic("filtered")
+	})
+	ol.SetLogLevel(OBJECT_LOG_LEVEL_TRACE)
+	defer func() {
+		assert.Equal(t, "oops 1", recover())
+		assert.Equal(t, "[PNC] oops 1\n", lg.Buffer().String())
+	}()
+	ol.LogPanic("oops %d", 1)
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, expect := range map[string]ObjectLogLevel{
+		"trace":     OBJECT_LOG_LEVEL_TRACE,
+		"DEBUG":     OBJECT_LOG_LEVEL_DEBUG,
+		" Info ":    OBJECT_LOG_LEVEL_INFO,
+		"notice":    OBJECT_LOG_LEVEL_NOTICE,
+		"warning":   OBJECT_LOG_LEVEL_WARN,
+		"Err":       OBJECT_LOG_LEVEL_ERROR,
+		"crit":      OBJECT_LOG_LEVEL_FATAL,
+		"panic":     OBJECT_LOG_LEVEL_PANIC,
+		"audit":     testLevelAudit,
+		"test-LOUD": testLevelLoud,
+	} {
+		level, err := ParseLevel(name)
+		assert.Nil(t, err, name)
+		assert.Equal(t, expect, level, name)
+	}
+	_, err := ParseLevel("nope")
+	assert.EqualError(t, err, `objectlog: unknown level "nope"`)
+
+	RegisterLevelAlias("Verbose", OBJECT_LOG_LEVEL_TRACE)
+	level, err := ParseLevel("VERBOSE")
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_LOG_LEVEL_TRACE, level)
+}
+
+func TestParseLevelEnv(t *testing.T) {
+	os.Setenv("OBJECTLOG_TEST_LEVEL", "")
+	level, err := ParseLevelEnv("OBJECTLOG_TEST_LEVEL", OBJECT_LOG_LEVEL_INFO)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_LOG_LEVEL_INFO, level)
+	os.Setenv("OBJECTLOG_TEST_LEVEL", "wrn")
+	defer os.Unsetenv("OBJECTLOG_TEST_LEVEL")
+	level, err = ParseLevelEnv("OBJECTLOG_TEST_LEVEL", OBJECT_LOG_LEVEL_INFO)
+	assert.Nil(t, err)
+	assert.Equal(t, OBJECT_LOG_LEVEL_WARN, level)
+}
+
+func TestObjectLogLevel_Compare(t *testing.T) {
+	assert.Equal(t, -1, OBJECT_LOG_LEVEL_DEBUG.Compare(OBJECT_LOG_LEVEL_INFO))
+	assert.Equal(t, 0, OBJECT_LOG_LEVEL_INFO.Compare(OBJECT_LOG_LEVEL_INFO))
+	assert.Equal(t, 1, OBJECT_LOG_LEVEL_FATAL.Compare(OBJECT_LOG_LEVEL_PANIC))
+	assert.Equal(t, 45, testLevelAudit.Severity())
+	assert.True(t, OBJECT_LOG_LEVEL_WARN.Enabled(OBJECT_LOG_LEVEL_INFO))
+	assert.True(t, OBJECT_LOG_LEVEL_WARN.Enabled(OBJECT_LOG_LEVEL_WARN))
+	assert.False(t, OBJECT_LOG_LEVEL_WARN.Enabled(OBJECT_LOG_LEVEL_ERROR))
+	assert.True(t, OBJECT_LOG_LEVEL_TRACE.Enabled(""))
+}
+
+func TestObjectLogLevel_Flag(t *testing.T) {
+	level := OBJECT_LOG_LEVEL_INFO
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.Var(&level, "level", "")
+	assert.Nil(t, flags.Parse([]string{"-level", "warning"}))
+	assert.Equal(t, OBJECT_LOG_LEVEL_WARN, level)
+	assert.Equal(t, "warn", flags.Lookup("level").Value.String())
+	assert.NotNil(t, flags.Parse([]string{"-level", "nope"}))
+}
+
+func TestObjectLogLevel_JSON(t *testing.T) {
+	var config struct {
+		Level ObjectLogLevel `json:"level"`
+		Other ObjectLogLevel `json:"other"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(`{"level":"ERR","other":""}`), &config))
+	assert.Equal(t, OBJECT_LOG_LEVEL_ERROR, config.Level)
+	assert.Equal(t, ObjectLogLevel(""), config.Other)
+	raw, err := json.Marshal(config)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"level":"error","other":""}`, string(raw))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"level":"nope"}`), &config))
+
+	levels := map[ObjectLogLevel]int{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"Warning":1}`), &levels))
+	assert.Equal(t, map[ObjectLogLevel]int{OBJECT_LOG_LEVEL_WARN: 1}, levels)
+}
@@ -1,55 +1,232 @@
 package objectlog
 
-import "bytes"
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+)
 
 type (
 
 	// BufferObjectLogger is useful for debugging & testing. It writes all logs in a simple format to
 	// the a buffer, which can later on be accessed to evaluate the input, for example to test if a
-	// certain log message has been written or not.
+	// certain log message has been written or not. Additionally all messages are recorded as entries,
+	// which can be queried:
+	//
+	//	lg := objectlog.NewBufferObjectLog()
+	//	objectlog.NewObjectLog(lg).SetLogArg("user", 123).LogWarn("Hello %s", "World")
+	//	lg.Count(objectlog.OBJECT_LOG_LEVEL_WARN) // 1
+	//	lg.Last().Args["user"]                     // 123
+	//	lg.Contains("Hello World")                 // true
 	BufferObjectLogger struct {
-		buf *bytes.Buffer
+		mutex   sync.Mutex
+		buf     *bytes.Buffer
+		entries []*ObjectLogEntry
+		lines   []string
+		changed chan struct{}
 	}
 )
 
 // NewBufferObjectLog creates new *BufferObjectLogger instance
 func NewBufferObjectLog() *BufferObjectLogger {
 	return &BufferObjectLogger{
-		buf: bytes.NewBuffer(nil),
+		buf:     bytes.NewBuffer(nil),
+		changed: make(chan struct{}),
 	}
 }
 
-// Buffer provides access to the accumulated buffer
+// Buffer provides access to the accumulated buffer. It is not safe to use while messages are written
+// concurrently, see `String` and `Lines`.
 func (this *BufferObjectLogger) Buffer() *bytes.Buffer {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
 	return this.buf
 }
 
-// Clear empties the buffer
+// String returns the contents of the accumulated buffer
+func (this *BufferObjectLogger) String() string {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.buf.String()
+}
+
+// Lines returns all lines written to the buffer, without line endings
+func (this *BufferObjectLogger) Lines() []string {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return append([]string{}, this.lines...)
+}
+
+// Clear empties the buffer and removes all entries
 func (this *BufferObjectLogger) Clear() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
 	this.buf = bytes.NewBuffer(nil)
+	this.entries = nil
+	this.lines = nil
+}
+
+// Entries returns all recorded entries in the order they were written
+func (this *BufferObjectLogger) Entries() []*ObjectLogEntry {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return append([]*ObjectLogEntry{}, this.entries...)
+}
+
+// Filter returns all recorded entries of exactly the level
+func (this *BufferObjectLogger) Filter(level ObjectLogLevel) []*ObjectLogEntry {
+	return this.Select(LevelsFilter(level))
+}
+
+// Select returns all recorded entries, which are accepted by the filter
+func (this *BufferObjectLogger) Select(filter ObjectLogFilter) []*ObjectLogEntry {
+	entries := []*ObjectLogEntry{}
+	for _, entry := range this.Entries() {
+		if filter(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Count returns the amount of recorded entries of exactly the level
+func (this *BufferObjectLogger) Count(level ObjectLogLevel) int {
+	return len(this.Filter(level))
+}
+
+// Contains returns whether any line written to the buffer contains the text
+func (this *BufferObjectLogger) Contains(text string) bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	for _, line := range this.lines {
+		if strings.Contains(line, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Last returns the last recorded entry or nil, if none was written
+func (this *BufferObjectLogger) Last() *ObjectLogEntry {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if len(this.entries) == 0 {
+		return nil
+	}
+	return this.entries[len(this.entries)-1]
+}
+
+// WaitFor waits until an entry accepted by the filter is recorded, or the timeout elapsed. Entries recorded
+// before the call are considered as well. It returns the first accepted entry or nil on timeout.
+//
+//	entry := lg.WaitFor(objectlog.LevelFilter(objectlog.OBJECT_LOG_LEVEL_ERROR), time.Second)
+func (this *BufferObjectLogger) WaitFor(filter ObjectLogFilter, timeout time.Duration) *ObjectLogEntry {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		this.mutex.Lock()
+		entries, changed := this.entries, this.changed
+		this.mutex.Unlock()
+		for _, entry := range entries {
+			if filter(entry) {
+				return entry
+			}
+		}
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return nil
+		}
+	}
+}
+
+// LogEntry records the entry and adds the formatted entry to the buffer like the method of the level
+func (this *BufferObjectLogger) LogEntry(entry *ObjectLogEntry) {
+	this.write(bufferTag(entry.Level), entry, entry.String())
+}
+
+// Trace adds the message to the buffer prefixed by "[TRC] " ended with new line
+func (this *BufferObjectLogger) Trace(msg string) {
+	this.writeText("[TRC] ", OBJECT_LOG_LEVEL_TRACE, msg)
 }
 
 // Debug adds the message to the buffer prefixed by "[DBG] " ended with new line
 func (this *BufferObjectLogger) Debug(msg string) {
-	this.buf.WriteString("[DBG] " + msg + "\n")
+	this.writeText("[DBG] ", OBJECT_LOG_LEVEL_DEBUG, msg)
 }
 
 // Info adds the message to the buffer prefixed by "[INF] " ended with new line
 func (this *BufferObjectLogger) Info(msg string) {
-	this.buf.WriteString("[INF] " + msg + "\n")
+	this.writeText("[INF] ", OBJECT_LOG_LEVEL_INFO, msg)
+}
+
+// Notice adds the message to the buffer prefixed by "[NTC] " ended with new line
+func (this *BufferObjectLogger) Notice(msg string) {
+	this.writeText("[NTC] ", OBJECT_LOG_LEVEL_NOTICE, msg)
 }
 
 // Warn adds the message to the buffer prefixed by "[WRN] " ended with new line
 func (this *BufferObjectLogger) Warn(msg string) {
-	this.buf.WriteString("[WRN] " + msg + "\n")
+	this.writeText("[WRN] ", OBJECT_LOG_LEVEL_WARN, msg)
 }
 
 // Error adds the message to the buffer prefixed by "[ERR] " ended with new line
 func (this *BufferObjectLogger) Error(msg string) {
-	this.buf.WriteString("[ERR] " + msg + "\n")
+	this.writeText("[ERR] ", OBJECT_LOG_LEVEL_ERROR, msg)
+}
+
+// Panic adds the message to the buffer prefixed by "[PNC] " ended with new line
+func (this *BufferObjectLogger) Panic(msg string) {
+	this.writeText("[PNC] ", OBJECT_LOG_LEVEL_PANIC, msg)
 }
 
 // Fatal adds the message to the buffer prefixed by "[FTL] " ended with new line. It DOES NOT EXIT (no call to os.exit)
 func (this *BufferObjectLogger) Fatal(msg string) {
-	this.buf.WriteString("[FTL] " + msg + "\n")
+	this.writeText("[FTL] ", OBJECT_LOG_LEVEL_FATAL, msg)
+}
+
+// Log adds the message to the buffer prefixed like the method of the level, or by the level name in
+// brackets for any other level, e.g. "[AUDIT] ", ended with new line
+func (this *BufferObjectLogger) Log(level ObjectLogLevel, msg string) {
+	this.writeText(bufferTag(level), level, msg)
+}
+
+func (this *BufferObjectLogger) writeText(tag string, level ObjectLogLevel, msg string) {
+	this.write(tag, NewTextEntry(level, msg), msg)
+}
+
+// write adds the line to the buffer, records the entry and wakes up all waiting for entries
+func (this *BufferObjectLogger) write(tag string, entry *ObjectLogEntry, text string) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	line := tag + text
+	this.buf.WriteString(line + "\n")
+	this.entries = append(this.entries, entry)
+	this.lines = append(this.lines, line)
+	close(this.changed)
+	this.changed = make(chan struct{})
+}
+
+// bufferTag returns the prefix of the level method, which writes the level
+func bufferTag(level ObjectLogLevel) string {
+	switch level {
+	case OBJECT_LOG_LEVEL_TRACE:
+		return "[TRC] "
+	case OBJECT_LOG_LEVEL_DEBUG:
+		return "[DBG] "
+	case OBJECT_LOG_LEVEL_INFO:
+		return "[INF] "
+	case OBJECT_LOG_LEVEL_NOTICE:
+		return "[NTC] "
+	case OBJECT_LOG_LEVEL_WARN:
+		return "[WRN] "
+	case OBJECT_LOG_LEVEL_ERROR:
+		return "[ERR] "
+	case OBJECT_LOG_LEVEL_PANIC:
+		return "[PNC] "
+	case OBJECT_LOG_LEVEL_FATAL:
+		return "[FTL] "
+	}
+	return "[" + LevelName(level) + "] "
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestBufferObjectLog(t *testing.T) {
@@ -13,11 +14,114 @@ func TestBufferObjectLog(t *testing.T) {
 	lg.Warn("From Warn")
 	lg.Error("From Error")
 	lg.Fatal("From Fatal")
+	lg.Trace("From Trace")
+	lg.Notice("From Notice")
+	lg.Panic("From Panic")
+	lg.Log(OBJECT_LOG_LEVEL_INFO, "From Log")
 	assert.Equal(t, strings.Join([]string{
 		"[DBG] From Debug",
 		"[INF] From Info",
 		"[WRN] From Warn",
 		"[ERR] From Error",
 		"[FTL] From Fatal",
+		"[TRC] From Trace",
+		"[NTC] From Notice",
+		"[PNC] From Panic",
+		"[INF] From Log",
 	}, "\n")+"\n", lg.Buffer().String())
 }
+
+func TestBufferObjectLog_Entries(t *testing.T) {
+	lg := NewBufferObjectLog()
+	assert.Nil(t, lg.Last())
+	ol := NewObjectLog(lg).SetLogPrefix("pre ").SetLogArg("user", 123)
+	ol.LogInfo("Hello %s", "World")
+	ol.LogWarn("careful")
+	ol.LogWarn("very careful")
+	lg.Error("direct")
+	assert.Equal(t, strings.Join([]string{
+		`[INF] pre Hello World :: {"user":123}`,
+		`[WRN] pre careful :: {"user":123}`,
+		`[WRN] pre very careful :: {"user":123}`,
+		`[ERR] direct`,
+	}, "\n")+"\n", lg.Buffer().String())
+
+	entries := lg.Entries()
+	if assert.Len(t, entries, 4) {
+		assert.Equal(t, OBJECT_LOG_LEVEL_INFO, entries[0].Level)
+		assert.Equal(t, "Hello World", entries[0].Text())
+		assert.Equal(t, "pre ", entries[0].Prefix)
+		assert.Equal(t, 123, entries[0].Args["user"])
+		assert.False(t, entries[0].Time.IsZero())
+	}
+	assert.Equal(t, 2, lg.Count(OBJECT_LOG_LEVEL_WARN))
+	assert.Equal(t, 0, lg.Count(OBJECT_LOG_LEVEL_DEBUG))
+	warnings := lg.Filter(OBJECT_LOG_LEVEL_WARN)
+	if assert.Len(t, warnings, 2) {
+		assert.Equal(t, "very careful", warnings[1].Text())
+	}
+	assert.Len(t, lg.Select(LevelFilter(OBJECT_LOG_LEVEL_WARN)), 3)
+	assert.True(t, lg.Contains("Hello World"))
+	assert.True(t, lg.Contains(`"user":123`))
+	assert.False(t, lg.Contains("Goodbye"))
+	assert.True(t, lg.Contains("[ERR] direct"), "matches the written line")
+	if last := lg.Last(); assert.NotNil(t, last) {
+		assert.Equal(t, OBJECT_LOG_LEVEL_ERROR, last.Level)
+		assert.Equal(t, "direct", last.Text())
+	}
+
+	lg.Clear()
+	assert.Empty(t, lg.Entries())
+	assert.Equal(t, "", lg.Buffer().String())
+	assert.False(t, lg.Contains("direct"))
+}
+
+func TestBufferObjectLog_String(t *testing.T) {
+	lg := NewBufferObjectLog()
+	lg.Info("first")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lg.Info("second")
+	}()
+	assert.True(t, strings.HasPrefix(lg.String(), "[INF] first\n"))
+	assert.Equal(t, "[INF] first", lg.Lines()[0])
+	<-done
+	assert.Equal(t, "[INF] first\n[INF] second\n", lg.String())
+	assert.Equal(t, []string{"[INF] first", "[INF] second"}, lg.Lines())
+
+	lg.Buffer().Reset()
+	assert.Equal(t, "", lg.String(), "buffer is the live buffer")
+}
+
+func TestBufferObjectLog_ArgsSnapshot(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogArg("state", "one")
+	ol.LogInfo("first")
+	ol.SetLogArg("state", "two")
+	assert.Equal(t, "one", lg.Last().Args["state"])
+	assert.True(t, lg.Contains(`"state":"one"`))
+}
+
+func TestBufferObjectLog_WaitFor(t *testing.T) {
+	lg := NewBufferObjectLog()
+	lg.Info("before")
+	entry := lg.WaitFor(func(entry *ObjectLogEntry) bool { return entry.Text() == "before" }, time.Second)
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, OBJECT_LOG_LEVEL_INFO, entry.Level)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		lg.Info("async info")
+		lg.Error("async error")
+	}()
+	entry = lg.WaitFor(LevelFilter(OBJECT_LOG_LEVEL_ERROR), 2*time.Second)
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, "async error", entry.Text())
+	}
+
+	start := time.Now()
+	assert.Nil(t, lg.WaitFor(LevelFilter(OBJECT_LOG_LEVEL_FATAL), 20*time.Millisecond))
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+}
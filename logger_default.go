@@ -0,0 +1,67 @@
+package objectlog
+
+import (
+	"sync"
+)
+
+/*
+DefaultProxyLogger resolves `DefaultLogger` whenever a message is written, instead of when the `ObjectLog` is
+created. It is used by `NewObjectLog`, if no logger is provided, so that objects created on package
+initialization follow a later replacement of the default logger with `SetDefaultLogger`.
+
+	var ol = objectlog.NewObjectLog() // package init
+	func main() {
+		objectlog.SetDefaultLogger(objectlog.NewWriterLogger(os.Stdout, objectlog.JSONFormatter))
+		ol.LogInfo("written as JSON to stdout")
+	}
+*/
+type (
+	DefaultProxyLogger struct {
+		entryLevelMethods
+	}
+)
+
+var (
+	defaultLoggerMutex sync.RWMutex
+	defaultProxy       = NewDefaultProxyLogger()
+	defaultFallback    = NewStandardLogger()
+)
+
+// NewDefaultProxyLogger creates new *DefaultProxyLogger
+func NewDefaultProxyLogger() *DefaultProxyLogger {
+	this := &DefaultProxyLogger{}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	return this
+}
+
+// SetDefaultLogger replaces `DefaultLogger` and returns the previous logger. Contrary to assigning
+// `DefaultLogger` directly, it is safe for concurrent use while messages are written.
+func SetDefaultLogger(logger ObjectLogger) ObjectLogger {
+	defaultLoggerMutex.Lock()
+	defer defaultLoggerMutex.Unlock()
+	previous := DefaultLogger
+	DefaultLogger = logger
+	return previous
+}
+
+// CurrentDefaultLogger returns the current `DefaultLogger`, safe for concurrent use with `SetDefaultLogger`
+func CurrentDefaultLogger() ObjectLogger {
+	defaultLoggerMutex.RLock()
+	defer defaultLoggerMutex.RUnlock()
+	return DefaultLogger
+}
+
+// Logger returns the logger messages are currently written to. If `DefaultLogger` is nil or itself a
+// *DefaultProxyLogger, then a `*StandardObjectLogger` writing to stderr is returned.
+func (this *DefaultProxyLogger) Logger() ObjectLogger {
+	logger := CurrentDefaultLogger()
+	if _, ok := logger.(*DefaultProxyLogger); ok || logger == nil {
+		return defaultFallback
+	}
+	return logger
+}
+
+// LogEntry writes the entry to the current `DefaultLogger`
+func (this *DefaultProxyLogger) LogEntry(entry *ObjectLogEntry) {
+	writeEntry(this.Logger(), entry)
+}
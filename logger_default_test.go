@@ -0,0 +1,72 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetDefaultLogger(t *testing.T) {
+	previous := CurrentDefaultLogger()
+	defer SetDefaultLogger(previous)
+
+	ol := NewObjectLog().SetLogArg("foo", "bar")
+	assert.IsType(t, &DefaultProxyLogger{}, ol.Logger())
+
+	buf1 := NewBufferObjectLog()
+	assert.Equal(t, previous, SetDefaultLogger(buf1))
+	assert.Equal(t, buf1, CurrentDefaultLogger())
+	assert.Equal(t, buf1, ol.Logger().(*DefaultProxyLogger).Logger())
+	ol.LogInfo("first")
+
+	buf2 := NewBufferObjectLog()
+	assert.Equal(t, buf1, SetDefaultLogger(buf2))
+	ol.LogWarn("second")
+	ol.LogChild("child", nil).LogNotice("third")
+
+	assert.Equal(t, `[INF] first :: {"foo":"bar"}`+"\n", buf1.Buffer().String())
+	assert.Equal(t, strings.Join([]string{
+		`[WRN] second :: {"foo":"bar"}`,
+		`[NTC] child: third :: {"foo":"bar"}`,
+	}, "\n")+"\n", buf2.Buffer().String())
+}
+
+func TestDefaultProxyLogger_Entry(t *testing.T) {
+	defer SetDefaultLogger(CurrentDefaultLogger())
+	lg := newTestEntryLogger()
+	SetDefaultLogger(lg)
+	NewObjectLog().SetLogArg("foo", "bar").LogError("hello")
+	if assert.Len(t, lg.entries, 1) {
+		assert.Equal(t, OBJECT_LOG_LEVEL_ERROR, lg.entries[0].Level)
+		assert.Equal(t, map[string]interface{}{"foo": "bar"}, lg.entries[0].Args)
+	}
+}
+
+func TestDefaultProxyLogger_Fallback(t *testing.T) {
+	defer SetDefaultLogger(CurrentDefaultLogger())
+	proxy := NewDefaultProxyLogger()
+	SetDefaultLogger(proxy)
+	assert.Equal(t, defaultFallback, proxy.Logger())
+	SetDefaultLogger(nil)
+	assert.Equal(t, defaultFallback, proxy.Logger())
+}
+
+func TestDefaultProxyLogger_Concurrent(t *testing.T) {
+	defer SetDefaultLogger(SetDefaultLogger(NewWriterLogger(ioutil.Discard, nil)))
+	ol := NewObjectLog()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetDefaultLogger(NewWriterLogger(ioutil.Discard, nil))
+		}()
+		go func() {
+			defer wg.Done()
+			ol.LogInfo("hello")
+		}()
+	}
+	wg.Wait()
+}
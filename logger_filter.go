@@ -0,0 +1,107 @@
+package objectlog
+
+import (
+	"fmt"
+	"path"
+)
+
+/*
+FilterLogger passes only messages, which are accepted by a filter function, on to another logger:
+
+	warnings := objectlog.NewFilterLogger(objectlog.NewStandardLogger(), objectlog.LevelFilter(objectlog.OBJECT_LOG_LEVEL_WARN))
+*/
+type (
+	FilterLogger struct {
+		entryLevelMethods
+		logger ObjectLogger
+		filter ObjectLogFilter
+	}
+
+	// ObjectLogFilter returns whether an entry should be written
+	ObjectLogFilter func(entry *ObjectLogEntry) bool
+)
+
+// NewFilterLogger creates new *FilterLogger, which writes only entries accepted by the filter to the logger
+func NewFilterLogger(logger ObjectLogger, filter ObjectLogFilter) *FilterLogger {
+	this := &FilterLogger{
+		logger: logger,
+		filter: filter,
+	}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	return this
+}
+
+// LevelFilter returns a filter, which accepts all entries of at least the given level
+func LevelFilter(min ObjectLogLevel) ObjectLogFilter {
+	return func(entry *ObjectLogEntry) bool {
+		return entry.Level.Enabled(min)
+	}
+}
+
+// LevelRangeFilter returns a filter, which accepts all entries from the min level up to including the max
+// level. If max is empty, there is no upper bound.
+func LevelRangeFilter(min, max ObjectLogLevel) ObjectLogFilter {
+	return func(entry *ObjectLogEntry) bool {
+		return entry.Level.Enabled(min) && (max == "" || entry.Level.Compare(max) <= 0)
+	}
+}
+
+// LevelsFilter returns a filter, which accepts entries of exactly the given levels
+func LevelsFilter(levels ...ObjectLogLevel) ObjectLogFilter {
+	accept := make(map[ObjectLogLevel]bool, len(levels))
+	for _, level := range levels {
+		accept[level] = true
+	}
+	return func(entry *ObjectLogEntry) bool {
+		return accept[entry.Level]
+	}
+}
+
+// PrefixFilter returns a filter, which accepts entries with a prefix matching the glob pattern, see
+// `path.Match`. Invalid patterns match nothing.
+func PrefixFilter(pattern string) ObjectLogFilter {
+	return func(entry *ObjectLogEntry) bool {
+		match, _ := path.Match(pattern, entry.Prefix)
+		return match
+	}
+}
+
+// ArgFilter returns a filter, which accepts entries having the log argument. If values are given, then the
+// argument must be equal to one of them, compared by their string representation.
+func ArgFilter(key string, values ...interface{}) ObjectLogFilter {
+	accept := make(map[string]bool, len(values))
+	for _, value := range values {
+		accept[fmt.Sprint(value)] = true
+	}
+	return func(entry *ObjectLogEntry) bool {
+		value, ok := entry.Fields[key]
+		if !ok {
+			value, ok = entry.Args[key]
+		}
+		return ok && (len(accept) == 0 || accept[fmt.Sprint(value)])
+	}
+}
+
+// AllFilters returns a filter, which accepts entries accepted by all filters
+func AllFilters(filters ...ObjectLogFilter) ObjectLogFilter {
+	return func(entry *ObjectLogEntry) bool {
+		for _, filter := range filters {
+			if !filter(entry) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Logger returns the logger, the accepted messages are written to
+func (this *FilterLogger) Logger() ObjectLogger {
+	return this.logger
+}
+
+// LogEntry writes the entry to the logger, if accepted by the filter
+func (this *FilterLogger) LogEntry(entry *ObjectLogEntry) {
+	if this.filter(entry) {
+		writeEntry(this.logger, entry)
+	}
+}
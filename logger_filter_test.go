@@ -0,0 +1,52 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFilterLogger(t *testing.T) {
+	buf := NewBufferObjectLog()
+	lg := NewFilterLogger(buf, LevelFilter(OBJECT_LOG_LEVEL_WARN))
+	assert.Equal(t, buf, lg.Logger())
+	ol := NewObjectLog(lg)
+	ol.LogInfo("hidden")
+	ol.LogWarn("warn")
+	lg.Debug("hidden")
+	lg.Error("error")
+	assert.Equal(t, strings.Join([]string{
+		"[WRN] warn",
+		"[ERR] error",
+	}, "\n")+"\n", buf.Buffer().String())
+}
+
+func TestFilters(t *testing.T) {
+	entry := func(level ObjectLogLevel, prefix string, args map[string]interface{}) *ObjectLogEntry {
+		return &ObjectLogEntry{Level: level, Prefix: prefix, Args: args}
+	}
+	between := LevelRangeFilter(OBJECT_LOG_LEVEL_DEBUG, OBJECT_LOG_LEVEL_INFO)
+	assert.False(t, between(entry(OBJECT_LOG_LEVEL_TRACE, "", nil)))
+	assert.True(t, between(entry(OBJECT_LOG_LEVEL_DEBUG, "", nil)))
+	assert.True(t, between(entry(OBJECT_LOG_LEVEL_INFO, "", nil)))
+	assert.False(t, between(entry(OBJECT_LOG_LEVEL_NOTICE, "", nil)))
+	assert.True(t, LevelRangeFilter(OBJECT_LOG_LEVEL_WARN, "")(entry(OBJECT_LOG_LEVEL_FATAL, "", nil)))
+
+	levels := LevelsFilter(OBJECT_LOG_LEVEL_INFO, OBJECT_LOG_LEVEL_FATAL)
+	assert.True(t, levels(entry(OBJECT_LOG_LEVEL_FATAL, "", nil)))
+	assert.False(t, levels(entry(OBJECT_LOG_LEVEL_ERROR, "", nil)))
+
+	assert.True(t, PrefixFilter("db*")(entry(OBJECT_LOG_LEVEL_INFO, "db: ", nil)))
+	assert.False(t, PrefixFilter("db*")(entry(OBJECT_LOG_LEVEL_INFO, "http: ", nil)))
+	assert.False(t, PrefixFilter("[")(entry(OBJECT_LOG_LEVEL_INFO, "[", nil)))
+
+	assert.True(t, ArgFilter("foo")(entry(OBJECT_LOG_LEVEL_INFO, "", map[string]interface{}{"foo": nil})))
+	assert.False(t, ArgFilter("foo")(entry(OBJECT_LOG_LEVEL_INFO, "", nil)))
+	assert.True(t, ArgFilter("foo", 1, 2)(entry(OBJECT_LOG_LEVEL_INFO, "", map[string]interface{}{"foo": "2"})))
+	assert.False(t, ArgFilter("foo", 1, 2)(entry(OBJECT_LOG_LEVEL_INFO, "", map[string]interface{}{"foo": 3})))
+
+	all := AllFilters(LevelFilter(OBJECT_LOG_LEVEL_WARN), ArgFilter("foo"))
+	assert.True(t, all(entry(OBJECT_LOG_LEVEL_ERROR, "", map[string]interface{}{"foo": 1})))
+	assert.False(t, all(entry(OBJECT_LOG_LEVEL_INFO, "", map[string]interface{}{"foo": 1})))
+	assert.True(t, AllFilters()(entry(OBJECT_LOG_LEVEL_INFO, "", nil)))
+}
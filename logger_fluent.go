@@ -0,0 +1,326 @@
+package objectlog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+/*
+FluentLogger sends messages with the Fluentd forward protocol to Fluentd or Fluent Bit. Messages are collected
+per tag and sent in PackedForward mode, optionally waiting for an acknowledgement of the server. The tag is
+rendered from a template, which is executed with a `TemplateEntry`.
+
+	lg, err := objectlog.NewFluentLogger("tcp", "localhost:24224", objectlog.FluentOptions{
+		Tag:        `myapp.{{ .Level }}`,
+		BatchSize:  100,
+		RequireAck: true,
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer lg.Close()
+	ol := objectlog.NewObjectLog(lg)
+*/
+type (
+	FluentLogger struct {
+		entryLevelMethods
+		errorReporting
+		network string
+		address string
+		options FluentOptions
+		tag     *template.Template
+		mutex   sync.Mutex
+		conn    net.Conn
+		reader  *bufio.Reader
+		tags    []string
+		pending map[string]*fluentChunk
+		count   int
+		stop    chan struct{}
+		done    chan struct{}
+		closed  bool
+	}
+
+	// FluentOptions configure a *FluentLogger
+	FluentOptions struct {
+
+		// Tag is a `text/template`, which is executed with a `TemplateEntry` and the functions of
+		// `TemplateFuncs`, e.g. `myapp.{{ .Args.component }}`. Defaults to "objectlog".
+		Tag string
+
+		// BatchSize is the amount of messages collected before they are sent, defaults to 1
+		BatchSize int
+
+		// Interval is the maximum time messages are collected before they are sent, defaults to 1 second
+		Interval time.Duration
+
+		// RequireAck waits for the acknowledgement of each chunk by the server
+		RequireAck bool
+
+		// Timeout of dial, write and acknowledgement, defaults to 5 seconds
+		Timeout time.Duration
+	}
+
+	// fluentChunk are the encoded entries of a tag
+	fluentChunk struct {
+		data []byte
+		size int
+	}
+)
+
+// NewFluentLogger creates new *FluentLogger sending to the address via the network, usually "tcp" or "unix"
+func NewFluentLogger(network, address string, options FluentOptions) (*FluentLogger, error) {
+	if options.Tag == "" {
+		options.Tag = "objectlog"
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1
+	}
+	if options.Interval <= 0 {
+		options.Interval = time.Second
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 5 * time.Second
+	}
+	tag, err := template.New("tag").Funcs(TemplateFuncs).Option("missingkey=zero").Parse(options.Tag)
+	if err != nil {
+		return nil, err
+	}
+	if err := tag.Execute(&bytes.Buffer{}, &TemplateEntry{Level: OBJECT_LOG_LEVEL_INFO, Args: map[string]interface{}{}}); err != nil {
+		return nil, err
+	}
+	this := &FluentLogger{
+		network: network,
+		address: address,
+		options: options,
+		tag:     tag,
+		pending: map[string]*fluentChunk{},
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	if err := this.connect(); err != nil {
+		return nil, err
+	}
+	go this.loop()
+	return this, nil
+}
+
+// SetErrorHandler sets the handler of write failures. If nil, then the global handler is used, see
+// `ErrorHandler`.
+func (this *FluentLogger) SetErrorHandler(handler ErrorHandler) *FluentLogger {
+	this.setErrorHandler(handler)
+	return this
+}
+
+// LogEntry adds the entry to the batch of its tag and sends all batches, if the batch size is reached.
+// FATAL entries close the logger and exit.
+func (this *FluentLogger) LogEntry(entry *ObjectLogEntry) {
+	this.report(this, this.add(entry))
+	if entry.Level == OBJECT_LOG_LEVEL_FATAL {
+		this.Close()
+		os.Exit(1)
+	}
+}
+
+// WriteEntry sends the entry immediately in a message of its own, regardless of the batch size, after
+// the collected messages. It returns the error of sending the entry, so that a nil error means the entry
+// was delivered, as required by `*SpoolLogger`.
+func (this *FluentLogger) WriteEntry(entry *ObjectLogEntry) error {
+	tag, data := this.encode(entry)
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.closed {
+		return fmt.Errorf("objectlog: fluent logger is closed")
+	}
+	this.report(this, this.flush())
+	return this.send(tag, &fluentChunk{data: data, size: 1})
+}
+
+// add adds the entry to the batch of its tag and sends all batches, if the batch size is reached. It
+// returns the error of sending.
+func (this *FluentLogger) add(entry *ObjectLogEntry) error {
+	tag, data := this.encode(entry)
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.closed {
+		return fmt.Errorf("objectlog: fluent logger is closed")
+	}
+	chunk, ok := this.pending[tag]
+	if !ok {
+		chunk = &fluentChunk{}
+		this.pending[tag] = chunk
+		this.tags = append(this.tags, tag)
+	}
+	chunk.data = append(chunk.data, data...)
+	chunk.size++
+	if this.count++; this.count >= this.options.BatchSize {
+		return this.flush()
+	}
+	return nil
+}
+
+// Flush sends all collected messages
+func (this *FluentLogger) Flush() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.flush()
+}
+
+// Close sends all collected messages and closes the connection
+func (this *FluentLogger) Close() error {
+	this.mutex.Lock()
+	if this.closed {
+		this.mutex.Unlock()
+		return nil
+	}
+	this.closed = true
+	close(this.stop)
+	this.mutex.Unlock()
+	<-this.done
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	err := this.flush()
+	if this.conn != nil {
+		this.conn.Close()
+		this.conn = nil
+	}
+	return err
+}
+
+func (this *FluentLogger) loop() {
+	defer close(this.done)
+	ticker := time.NewTicker(this.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-this.stop:
+			return
+		case <-ticker.C:
+			this.report(this, this.Flush())
+		}
+	}
+}
+
+// encode returns the tag and the encoded event of the entry
+func (this *FluentLogger) encode(entry *ObjectLogEntry) (string, []byte) {
+	fields := formatterFields(entry.Time, entry.Level, entry.Prefix, entry.Suffix, entry.Text())
+	delete(fields, "time")
+	data := msgpackHeader(nil, 2, 0x90, 16, 0xdc)
+	data = msgpackEncode(data, fluentEventTime(entry.Time))
+	data = msgpackEncode(data, mergeRecord(fields, entry.LogArgs()))
+	return this.renderTag(entry), data
+}
+
+// renderTag executes the tag template, falls back to "objectlog" if it fails or renders empty
+func (this *FluentLogger) renderTag(entry *ObjectLogEntry) string {
+	args := entry.LogArgs()
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	buf := &bytes.Buffer{}
+	err := this.tag.Execute(buf, &TemplateEntry{
+		Level:   entry.Level,
+		Prefix:  entry.Prefix,
+		Suffix:  entry.Suffix,
+		Message: entry.Text(),
+		Args:    args,
+		Time:    entry.Time,
+		pcs:     entry.callers,
+	})
+	if tag := strings.TrimSpace(buf.String()); err == nil && tag != "" {
+		return tag
+	}
+	return "objectlog"
+}
+
+// flush sends the pending chunks of all tags, dropping those which cannot be sent
+func (this *FluentLogger) flush() error {
+	var err error
+	for _, tag := range this.tags {
+		if serr := this.send(tag, this.pending[tag]); serr != nil {
+			err = serr
+		}
+	}
+	this.tags = nil
+	this.pending = map[string]*fluentChunk{}
+	this.count = 0
+	return err
+}
+
+// send writes a PackedForward message, reconnecting once if it fails
+func (this *FluentLogger) send(tag string, chunk *fluentChunk) error {
+	option := map[string]interface{}{"size": chunk.size}
+	id := ""
+	if this.options.RequireAck {
+		raw := make([]byte, 16)
+		if _, err := rand.Read(raw); err != nil {
+			return err
+		}
+		id = base64.StdEncoding.EncodeToString(raw)
+		option["chunk"] = id
+	}
+	message := msgpackHeader(nil, 3, 0x90, 16, 0xdc)
+	message = msgpackString(message, tag)
+	message = msgpackBin(message, chunk.data)
+	message = msgpackEncode(message, option)
+
+	err := this.write(message, id)
+	if err != nil {
+		if err = this.connect(); err == nil {
+			err = this.write(message, id)
+		}
+	}
+	return err
+}
+
+func (this *FluentLogger) write(message []byte, id string) error {
+	if this.conn == nil {
+		if err := this.connect(); err != nil {
+			return err
+		}
+	}
+	this.conn.SetDeadline(time.Now().Add(this.options.Timeout))
+	if _, err := this.conn.Write(message); err != nil {
+		return err
+	} else if id == "" {
+		return nil
+	}
+	response, err := msgpackDecode(this.reader)
+	if err != nil {
+		return err
+	}
+	if data, ok := response.(map[string]interface{}); !ok || data["ack"] != id {
+		return fmt.Errorf("objectlog: invalid fluent acknowledgement %v", response)
+	}
+	return nil
+}
+
+func (this *FluentLogger) connect() error {
+	if this.conn != nil {
+		this.conn.Close()
+		this.conn = nil
+	}
+	conn, err := net.DialTimeout(this.network, this.address, this.options.Timeout)
+	if err != nil {
+		return err
+	}
+	this.conn = conn
+	this.reader = bufio.NewReader(conn)
+	return nil
+}
+
+// fluentEventTime returns the EventTime extension of the forward protocol with nanosecond precision
+func fluentEventTime(ts time.Time) msgpackExt {
+	data := msgpackAppend32(nil, uint32(ts.Unix()))
+	return msgpackExt{0, msgpackAppend32(data, uint32(ts.Nanosecond()))}
+}
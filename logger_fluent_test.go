@@ -0,0 +1,194 @@
+package objectlog
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+// testFluentMessage is a decoded PackedForward message
+type testFluentMessage struct {
+	tag     string
+	times   []time.Time
+	records []map[string]interface{}
+	option  map[string]interface{}
+}
+
+// testFluentServer is an in-process forward server, which acknowledges chunks unless ack is false
+func testFluentServer(t *testing.T, ack bool) (net.Listener, <-chan *testFluentMessage) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make(chan *testFluentMessage, 100)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					value, err := msgpackDecode(reader)
+					if err != nil {
+						return
+					}
+					message := testFluentDecode(t, value)
+					if id, ok := message.option["chunk"]; ok && ack {
+						conn.Write(msgpackEncode(nil, map[string]interface{}{"ack": id}))
+					}
+					messages <- message
+				}
+			}(conn)
+		}
+	}()
+	return listener, messages
+}
+
+func testFluentDecode(t *testing.T, value interface{}) *testFluentMessage {
+	list := value.([]interface{})
+	message := &testFluentMessage{tag: list[0].(string), option: list[2].(map[string]interface{})}
+	reader := bufio.NewReader(bytes.NewReader(list[1].([]byte)))
+	for {
+		event, err := msgpackDecode(reader)
+		if err != nil {
+			break
+		}
+		pair := event.([]interface{})
+		ext := pair[0].(msgpackExt)
+		assert.Equal(t, int8(0), ext.Type)
+		message.times = append(message.times, time.Unix(int64(msgpackBytesUint(ext.Data[:4])), int64(msgpackBytesUint(ext.Data[4:]))))
+		message.records = append(message.records, pair[1].(map[string]interface{}))
+	}
+	return message
+}
+
+func testFluentReceive(t *testing.T, messages <-chan *testFluentMessage) *testFluentMessage {
+	select {
+	case message := <-messages:
+		return message
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for fluent message")
+	}
+	return nil
+}
+
+func TestFluentLogger(t *testing.T) {
+	listener, messages := testFluentServer(t, true)
+	defer listener.Close()
+	lg, err := NewFluentLogger("tcp", listener.Addr().String(), FluentOptions{
+		Tag:        `app.{{ .Level }}{{ with .Args.component }}.{{ . }}{{ end }}`,
+		RequireAck: true,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer lg.Close()
+	before := time.Now()
+	ol := NewObjectLog(lg).SetLogPrefix("pre ").SetLogArg("component", "db")
+	ol.LogWarn("hello %s", "you")
+
+	message := testFluentReceive(t, messages)
+	assert.Equal(t, "app.warn.db", message.tag)
+	assert.Equal(t, int64(1), message.option["size"])
+	assert.NotEmpty(t, message.option["chunk"])
+	if assert.Len(t, message.records, 1) {
+		assert.Equal(t, map[string]interface{}{
+			"level":     "warn",
+			"msg":       "hello you",
+			"prefix":    "pre ",
+			"component": "db",
+		}, message.records[0])
+		assert.False(t, message.times[0].Before(before.Truncate(time.Second)))
+	}
+
+	NewObjectLog(lg).LogInfo("plain")
+	assert.Equal(t, "app.info", testFluentReceive(t, messages).tag)
+}
+
+func TestFluentLogger_Batch(t *testing.T) {
+	listener, messages := testFluentServer(t, true)
+	defer listener.Close()
+	lg, err := NewFluentLogger("tcp", listener.Addr().String(), FluentOptions{
+		Tag:       `{{ .Level }}`,
+		BatchSize: 3,
+		Interval:  time.Hour,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	ol := NewObjectLog(lg)
+	ol.LogInfo("one")
+	ol.LogWarn("two")
+	ol.LogInfo("three")
+	ol.LogInfo("four")
+
+	info := testFluentReceive(t, messages)
+	warn := testFluentReceive(t, messages)
+	assert.Equal(t, "info", info.tag)
+	assert.Equal(t, int64(2), info.option["size"])
+	assert.NotContains(t, info.option, "chunk")
+	if assert.Len(t, info.records, 2) {
+		assert.Equal(t, "one", info.records[0]["msg"])
+		assert.Equal(t, "three", info.records[1]["msg"])
+	}
+	assert.Equal(t, "warn", warn.tag)
+
+	assert.Nil(t, lg.Close())
+	last := testFluentReceive(t, messages)
+	assert.Equal(t, "four", last.records[0]["msg"])
+	assert.NotNil(t, lg.WriteEntry(NewTextEntry(OBJECT_LOG_LEVEL_INFO, "closed")))
+}
+
+func TestFluentLogger_WriteEntry(t *testing.T) {
+	listener, messages := testFluentServer(t, true)
+	defer listener.Close()
+	lg, err := NewFluentLogger("tcp", listener.Addr().String(), FluentOptions{BatchSize: 100, Interval: time.Hour})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer lg.Close()
+	NewObjectLog(lg).LogInfo("batched")
+	assert.Nil(t, lg.WriteEntry(NewTextEntry(OBJECT_LOG_LEVEL_WARN, "direct")))
+	assert.Equal(t, "batched", testFluentReceive(t, messages).records[0]["msg"])
+	assert.Equal(t, "direct", testFluentReceive(t, messages).records[0]["msg"], "sent without waiting for the batch")
+}
+
+func TestFluentLogger_Interval(t *testing.T) {
+	listener, messages := testFluentServer(t, true)
+	defer listener.Close()
+	lg, err := NewFluentLogger("tcp", listener.Addr().String(), FluentOptions{BatchSize: 100, Interval: 10 * time.Millisecond})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer lg.Close()
+	NewObjectLog(lg).LogInfo("hello")
+	message := testFluentReceive(t, messages)
+	assert.Equal(t, "objectlog", message.tag)
+	assert.Equal(t, "hello", message.records[0]["msg"])
+}
+
+func TestFluentLogger_MissingAck(t *testing.T) {
+	listener, messages := testFluentServer(t, false)
+	defer listener.Close()
+	lg, err := NewFluentLogger("tcp", listener.Addr().String(), FluentOptions{RequireAck: true, Timeout: 50 * time.Millisecond})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer lg.Close()
+	assert.NotNil(t, lg.WriteEntry(NewTextEntry(OBJECT_LOG_LEVEL_INFO, "hello")))
+	testFluentReceive(t, messages)
+	testFluentReceive(t, messages)
+}
+
+func TestNewFluentLogger_Errors(t *testing.T) {
+	_, err := NewFluentLogger("tcp", "127.0.0.1:1", FluentOptions{Tag: "{{ .Nope }}"})
+	assert.NotNil(t, err)
+	_, err = NewFluentLogger("tcp", "127.0.0.1:1", FluentOptions{Tag: "{{"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,45 @@
+package objectlog
+
+/*
+FormatterLogger formats all messages with it's own formatter, instead of the formatter of the `ObjectLog`,
+before passing them on to another logger. It allows different formats per logger:
+
+	stderr := objectlog.NewFormatterLogger(objectlog.NewStandardLogger(), objectlog.DefaultFormatter)
+	file := objectlog.NewFormatterLogger(objectlog.NewStandardLogger(fileLog), objectlog.JSONFormatter)
+	obj := objectlog.NewObjectLog(objectlog.NewMultiLogger(stderr, file))
+*/
+type (
+	FormatterLogger struct {
+		entryLevelMethods
+		logger    ObjectLogger
+		formatter ObjectLogFormatter
+	}
+)
+
+// NewFormatterLogger creates new *FormatterLogger, which formats messages with the formatter before
+// writing them to the logger
+func NewFormatterLogger(logger ObjectLogger, formatter ObjectLogFormatter) *FormatterLogger {
+	this := &FormatterLogger{
+		logger:    logger,
+		formatter: formatter,
+	}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	return this
+}
+
+// Logger returns the logger, the formatted messages are written to
+func (this *FormatterLogger) Logger() ObjectLogger {
+	return this.logger
+}
+
+// Formatter returns the formatter
+func (this *FormatterLogger) Formatter() ObjectLogFormatter {
+	return this.formatter
+}
+
+// LogEntry writes the entry with replaced formatter to the logger
+func (this *FormatterLogger) LogEntry(entry *ObjectLogEntry) {
+	formatted := *entry
+	formatted.Formatter = this.formatter
+	writeEntry(this.logger, &formatted)
+}
@@ -0,0 +1,26 @@
+package objectlog
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFormatterLogger(t *testing.T) {
+	buf := NewBufferObjectLog()
+	lg := NewFormatterLogger(buf, func(level ObjectLogLevel, prefix, suffix, msg string, msgArgs []interface{}, logArgs map[string]interface{}) string {
+		return strings.ToUpper(prefix + fmt.Sprintf(msg, msgArgs...))
+	})
+	assert.Equal(t, buf, lg.Logger())
+	assert.NotNil(t, lg.Formatter())
+	ol := NewObjectLog(lg).SetLogPrefix("pre ").SetLogArg("foo", "bar")
+	ol.LogInfo("hello %s", "you")
+	ol.LogTrace("trace")
+	lg.Warn("direct")
+	assert.Equal(t, strings.Join([]string{
+		"[INF] PRE HELLO YOU",
+		"[TRC] PRE TRACE",
+		"[WRN] DIRECT",
+	}, "\n")+"\n", buf.Buffer().String())
+}
@@ -0,0 +1,237 @@
+package objectlog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+/*
+GelfLogger sends messages in the GELF 1.1 format to Graylog, either via UDP, optionally compressed and chunked,
+or via TCP, delimited by null bytes.
+
+	lg, err := objectlog.NewGelfLogger("udp", "graylog:12201", objectlog.GelfOptions{
+		Compression: objectlog.GELF_COMPRESSION_GZIP,
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer lg.Close()
+	ol := objectlog.NewObjectLog(lg)
+*/
+type (
+	GelfLogger struct {
+		entryLevelMethods
+		errorReporting
+		mutex   sync.Mutex
+		network string
+		address string
+		options GelfOptions
+		conn    net.Conn
+	}
+
+	// GelfOptions configure a *GelfLogger
+	GelfOptions struct {
+
+		// Host is the name of the host sending messages, defaults to the hostname of the machine
+		Host string
+
+		// Compression of UDP messages, TCP messages are never compressed
+		Compression GelfCompression
+
+		// ChunkSize is the maximum size of UDP packets, defaults to `GELF_CHUNK_SIZE`
+		ChunkSize int
+
+		// Timeout of dial and write, defaults to 5 seconds
+		Timeout time.Duration
+	}
+
+	// GelfCompression is the compression of GELF UDP messages
+	GelfCompression string
+)
+
+const (
+	GELF_COMPRESSION_NONE GelfCompression = ""
+	GELF_COMPRESSION_ZLIB GelfCompression = "zlib"
+	GELF_COMPRESSION_GZIP GelfCompression = "gzip"
+
+	// GELF_CHUNK_SIZE is the default maximum size of UDP packets, which fits into most networks
+	GELF_CHUNK_SIZE = 1420
+
+	// GELF_MAX_CHUNKS is the maximum number of chunks of a message, larger messages are dropped
+	GELF_MAX_CHUNKS = 128
+
+	// gelfChunkHeader is the size of the header of each chunk: magic bytes, message ID, sequence number and count
+	gelfChunkHeader = 12
+)
+
+// NewGelfLogger creates new *GelfLogger sending to the address via "udp" or "tcp" network
+func NewGelfLogger(network, address string, options GelfOptions) (*GelfLogger, error) {
+	switch network {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("objectlog: unsupported GELF network %q", network)
+	}
+	switch options.Compression {
+	case GELF_COMPRESSION_NONE, GELF_COMPRESSION_ZLIB, GELF_COMPRESSION_GZIP:
+	default:
+		return nil, fmt.Errorf("objectlog: unsupported GELF compression %q", options.Compression)
+	}
+	if options.ChunkSize == 0 {
+		options.ChunkSize = GELF_CHUNK_SIZE
+	} else if options.ChunkSize <= gelfChunkHeader {
+		return nil, fmt.Errorf("objectlog: GELF chunk size must be larger than %d", gelfChunkHeader)
+	}
+	if options.Timeout == 0 {
+		options.Timeout = 5 * time.Second
+	}
+	options.Host = gelfHost(options.Host)
+	this := &GelfLogger{
+		network: network,
+		address: address,
+		options: options,
+	}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	if err := this.connect(); err != nil {
+		return nil, err
+	}
+	return this, nil
+}
+
+// SetErrorHandler sets the handler of write failures. If nil, then the global handler is used, see
+// `ErrorHandler`.
+func (this *GelfLogger) SetErrorHandler(handler ErrorHandler) *GelfLogger {
+	this.setErrorHandler(handler)
+	return this
+}
+
+// LogEntry sends the entry. FATAL entries exit after sending.
+func (this *GelfLogger) LogEntry(entry *ObjectLogEntry) {
+	this.report(this, this.WriteEntry(entry))
+	if entry.Level == OBJECT_LOG_LEVEL_FATAL {
+		os.Exit(1)
+	}
+}
+
+// WriteEntry sends the entry
+func (this *GelfLogger) WriteEntry(entry *ObjectLogEntry) error {
+	return this.Send(gelfMessage(this.options.Host, entry.Time, entry.Level, entry.Prefix, entry.Suffix, entry.Text(), entry.LogArgs()))
+}
+
+// Send writes a GELF JSON message to the connection. TCP connections are re-established once, if
+// writing fails.
+func (this *GelfLogger) Send(message []byte) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.isTCP() {
+		err := this.write(append(message, 0))
+		if err != nil {
+			if err = this.connect(); err == nil {
+				err = this.write(append(message, 0))
+			}
+		}
+		return err
+	}
+	message, err := this.compress(message)
+	if err != nil {
+		return err
+	}
+	if len(message) <= this.options.ChunkSize {
+		return this.write(message)
+	}
+	return this.writeChunks(message)
+}
+
+// Close closes the connection
+func (this *GelfLogger) Close() error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.conn == nil {
+		return nil
+	}
+	err := this.conn.Close()
+	this.conn = nil
+	return err
+}
+
+func (this *GelfLogger) isTCP() bool {
+	return this.network[:3] == "tcp"
+}
+
+func (this *GelfLogger) connect() error {
+	if this.conn != nil {
+		this.conn.Close()
+	}
+	conn, err := net.DialTimeout(this.network, this.address, this.options.Timeout)
+	if err != nil {
+		this.conn = nil
+		return err
+	}
+	this.conn = conn
+	return nil
+}
+
+func (this *GelfLogger) write(data []byte) error {
+	if this.conn == nil {
+		if err := this.connect(); err != nil {
+			return err
+		}
+	}
+	this.conn.SetWriteDeadline(time.Now().Add(this.options.Timeout))
+	_, err := this.conn.Write(data)
+	return err
+}
+
+func (this *GelfLogger) compress(message []byte) ([]byte, error) {
+	if this.options.Compression == GELF_COMPRESSION_NONE {
+		return message, nil
+	}
+	buf := &bytes.Buffer{}
+	var writer io.WriteCloser
+	if this.options.Compression == GELF_COMPRESSION_GZIP {
+		writer = gzip.NewWriter(buf)
+	} else {
+		writer = zlib.NewWriter(buf)
+	}
+	if _, err := writer.Write(message); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (this *GelfLogger) writeChunks(message []byte) error {
+	size := this.options.ChunkSize - gelfChunkHeader
+	count := (len(message) + size - 1) / size
+	if count > GELF_MAX_CHUNKS {
+		return fmt.Errorf("objectlog: GELF message of %d bytes exceeds %d chunks", len(message), GELF_MAX_CHUNKS)
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	chunk := make([]byte, 0, this.options.ChunkSize)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * size
+		if end > len(message) {
+			end = len(message)
+		}
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, message[i*size:end]...)
+		if err := this.write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package objectlog
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testGelfUDPServer reassembles chunked and decompresses GELF messages received via UDP
+func testGelfUDPServer(t *testing.T) (*net.UDPConn, <-chan map[string]interface{}) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make(chan map[string]interface{}, 10)
+	go func() {
+		chunks := map[string][][]byte{}
+		buf := make([]byte, 65536)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			packet := append([]byte{}, buf[:n]...)
+			if len(packet) > 12 && packet[0] == 0x1e && packet[1] == 0x0f {
+				id, seq, count := string(packet[2:10]), int(packet[10]), int(packet[11])
+				if chunks[id] == nil {
+					chunks[id] = make([][]byte, count)
+				}
+				chunks[id][seq] = packet[12:]
+				complete := true
+				for _, chunk := range chunks[id] {
+					complete = complete && chunk != nil
+				}
+				if !complete {
+					continue
+				}
+				packet = bytes.Join(chunks[id], nil)
+				delete(chunks, id)
+			}
+			messages <- testGelfDecode(t, packet)
+		}
+	}()
+	return conn, messages
+}
+
+func testGelfDecode(t *testing.T, packet []byte) map[string]interface{} {
+	if packet[0] == 0x1f && packet[1] == 0x8b {
+		reader, err := gzip.NewReader(bytes.NewReader(packet))
+		assert.Nil(t, err)
+		packet, _ = ioutil.ReadAll(reader)
+	} else if packet[0] == 0x78 {
+		reader, err := zlib.NewReader(bytes.NewReader(packet))
+		assert.Nil(t, err)
+		packet, _ = ioutil.ReadAll(reader)
+	}
+	data := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(packet, &data), string(packet))
+	return data
+}
+
+func testGelfReceive(t *testing.T, messages <-chan map[string]interface{}) map[string]interface{} {
+	select {
+	case message := <-messages:
+		return message
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for GELF message")
+	}
+	return nil
+}
+
+func TestGelfLogger_UDP(t *testing.T) {
+	for _, compression := range []GelfCompression{GELF_COMPRESSION_NONE, GELF_COMPRESSION_ZLIB, GELF_COMPRESSION_GZIP} {
+		conn, messages := testGelfUDPServer(t)
+		lg, err := NewGelfLogger("udp", conn.LocalAddr().String(), GelfOptions{
+			Host:        "myhost",
+			Compression: compression,
+			ChunkSize:   100,
+		})
+		if !assert.Nil(t, err) {
+			conn.Close()
+			continue
+		}
+		ol := NewObjectLog(lg).SetLogPrefix("pre ").SetLogArg("foo", "bar")
+
+		ol.LogInfo("short")
+		message := testGelfReceive(t, messages)
+		assert.Equal(t, "short", message["short_message"], string(compression))
+		assert.Equal(t, "myhost", message["host"])
+		assert.Equal(t, "pre ", message["_prefix"])
+		assert.Equal(t, "bar", message["_foo"])
+		assert.Equal(t, float64(6), message["level"])
+
+		noise := make([]byte, 1000)
+		rand.Read(noise)
+		long := hex.EncodeToString(noise)
+		ol.LogError(long)
+		message = testGelfReceive(t, messages)
+		assert.Equal(t, long, message["short_message"], string(compression))
+		assert.Equal(t, float64(3), message["level"])
+
+		assert.Nil(t, lg.Close())
+		conn.Close()
+	}
+}
+
+func TestGelfLogger_UDPTooManyChunks(t *testing.T) {
+	conn, _ := testGelfUDPServer(t)
+	defer conn.Close()
+	lg, err := NewGelfLogger("udp", conn.LocalAddr().String(), GelfOptions{ChunkSize: 13})
+	if assert.Nil(t, err) {
+		defer lg.Close()
+		err = lg.Send(bytes.Repeat([]byte("x"), 200))
+		if assert.NotNil(t, err) {
+			assert.Equal(t, "objectlog: GELF message of 200 bytes exceeds 128 chunks", err.Error())
+		}
+	}
+}
+
+func TestGelfLogger_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer listener.Close()
+	messages := make(chan map[string]interface{}, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					raw, err := reader.ReadBytes(0)
+					if err != nil {
+						return
+					}
+					messages <- testGelfDecode(t, raw[:len(raw)-1])
+				}
+			}(conn)
+		}
+	}()
+
+	lg, err := NewGelfLogger("tcp", listener.Addr().String(), GelfOptions{Compression: GELF_COMPRESSION_GZIP})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer lg.Close()
+	ol := NewObjectLog(lg).SetLogArg("foo", "bar")
+	ol.LogWarn("first")
+	ol.LogNotice(strings.Repeat("x", 5000))
+	message := testGelfReceive(t, messages)
+	assert.Equal(t, "first", message["short_message"])
+	assert.Equal(t, float64(4), message["level"])
+	message = testGelfReceive(t, messages)
+	assert.Equal(t, strings.Repeat("x", 5000), message["short_message"])
+	assert.Equal(t, float64(5), message["level"])
+}
+
+func TestNewGelfLogger_Errors(t *testing.T) {
+	for options, expect := range map[string]string{
+		"unix":     `objectlog: unsupported GELF network "unix"`,
+		"lzma":     `objectlog: unsupported GELF compression "lzma"`,
+		"chunk:12": `objectlog: GELF chunk size must be larger than 12`,
+	} {
+		network, gelfOptions := "udp", GelfOptions{}
+		switch options {
+		case "unix":
+			network = "unix"
+		case "lzma":
+			gelfOptions.Compression = "lzma"
+		default:
+			gelfOptions.ChunkSize = 12
+		}
+		_, err := NewGelfLogger(network, "127.0.0.1:1", gelfOptions)
+		if assert.NotNil(t, err, options) {
+			assert.Equal(t, expect, err.Error())
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package objectlog
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+)
+
+/*
+HookLogger calls hooks with every message, before passing it on to another logger. Hooks can modify
+the message, e.g. to redact sensitive data, or trigger other actions:
+
+	redact := objectlog.NewRedactHook([]string{"password", "*token"}, nil)
+	lg := objectlog.NewHookLogger(objectlog.NewStandardLogger(), redact)
+*/
+type (
+	HookLogger struct {
+		entryLevelMethods
+		logger ObjectLogger
+		hooks  []ObjectLogHook
+	}
+
+	// ObjectLogHook is called with a copy of each entry, which it can modify
+	ObjectLogHook func(entry *ObjectLogEntry)
+)
+
+// NewHookLogger creates new *HookLogger, which calls the hooks in order for each entry before writing
+// it to the logger
+func NewHookLogger(logger ObjectLogger, hooks ...ObjectLogHook) *HookLogger {
+	this := &HookLogger{
+		logger: logger,
+		hooks:  hooks,
+	}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	return this
+}
+
+// AddHook adds another hook to the list
+func (this *HookLogger) AddHook(hook ObjectLogHook) *HookLogger {
+	this.hooks = append(this.hooks, hook)
+	return this
+}
+
+// Logger returns the logger, the messages are written to
+func (this *HookLogger) Logger() ObjectLogger {
+	return this.logger
+}
+
+// LogEntry calls all hooks with a copy of the entry and writes it to the logger
+func (this *HookLogger) LogEntry(entry *ObjectLogEntry) {
+	if len(this.hooks) > 0 {
+		entry = entry.Copy()
+		for _, hook := range this.hooks {
+			hook(entry)
+		}
+	}
+	writeEntry(this.logger, entry)
+}
+
+// NewRedactHook creates a hook, which replaces the values of all args whose names match any of the glob
+// patterns (see `path.Match`) with `RedactedLogValue`. All matches of the regular expressions in the
+// message, prefix, suffix and string args are replaced as well.
+//	objectlog.NewRedactHook([]string{"password"}, []*regexp.Regexp{regexp.MustCompile(`\d{16}`)})
+func NewRedactHook(keys []string, patterns []*regexp.Regexp) ObjectLogHook {
+	return func(entry *ObjectLogEntry) {
+		redacted := fmt.Sprint(RedactedLogValue)
+		redact := func(value string) string {
+			for _, pattern := range patterns {
+				value = pattern.ReplaceAllString(value, redacted)
+			}
+			return value
+		}
+		for key, value := range entry.Args {
+			if matchAny(keys, key) {
+				entry.Args[key] = RedactedLogValue
+			} else if str, ok := value.(string); ok {
+				entry.Args[key] = redact(str)
+			}
+		}
+		if len(patterns) > 0 {
+			text := redact(entry.Text())
+			entry.Message = "%s"
+			entry.MessageArgs = []interface{}{text}
+			entry.Prefix = redact(entry.Prefix)
+			entry.Suffix = redact(entry.Suffix)
+		}
+	}
+}
+
+// matchAny returns whether the name matches any of the glob patterns
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// NewArgsHook creates a hook, which adds the args to every entry, without overwriting existing args
+//	objectlog.NewArgsHook(map[string]interface{}{"app": "foo", "version": version})
+func NewArgsHook(args map[string]interface{}) ObjectLogHook {
+	return func(entry *ObjectLogEntry) {
+		for key, value := range args {
+			if _, ok := entry.Args[key]; !ok {
+				entry.Args[key] = value
+			}
+		}
+	}
+}
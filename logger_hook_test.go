@@ -0,0 +1,41 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestHookLogger(t *testing.T) {
+	buf := NewBufferObjectLog()
+	calls := 0
+	lg := NewHookLogger(buf, NewArgsHook(map[string]interface{}{"app": "test", "foo": "default"})).
+		AddHook(func(entry *ObjectLogEntry) {
+			calls++
+		})
+	assert.Equal(t, buf, lg.Logger())
+	ol := NewObjectLog(lg).SetLogArg("foo", "bar")
+	ol.LogInfo("one")
+	lg.Warn("two")
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, ol.LogArgs())
+	assert.Equal(t, strings.Join([]string{
+		`[INF] one :: {"app":"test","foo":"bar"}`,
+		`[WRN] two`,
+	}, "\n")+"\n", buf.Buffer().String())
+}
+
+func TestNewRedactHook(t *testing.T) {
+	buf := NewBufferObjectLog()
+	lg := NewHookLogger(buf, NewRedactHook([]string{"password", "*token"}, []*regexp.Regexp{regexp.MustCompile(`\d{4}-\d{4}`)}))
+	ol := NewObjectLog(lg).
+		SetLogPrefix("card 1234-5678: ").
+		SetLogArg("password", "secret").
+		SetLogArg("api_token", "abc").
+		SetLogArg("note", "paid with 1111-2222").
+		SetLogArg("count", 3)
+	ol.LogInfo("charged %s", "9999-8888")
+	assert.Equal(t, `[INF] card ***: charged *** :: {"api_token":"***","count":3,"note":"paid with ***","password":"***"}`+"\n", buf.Buffer().String())
+	assert.Equal(t, "secret", ol.LogArgs()["password"])
+}
@@ -0,0 +1,400 @@
+package objectlog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/*
+HTTPLogger collects messages in batches and sends each batch with a POST request to an HTTP endpoint. A batch is
+sent when it reaches the batch size, or when the interval elapsed. Failed requests are retried with exponential
+backoff. Writing blocks only if the maximum of requests is in flight.
+
+	lg, err := objectlog.NewHTTPLogger("http://loki:3100/loki/api/v1/push", objectlog.HTTPOptions{
+		Format: objectlog.HTTP_FORMAT_LOKI,
+		Labels: map[string]string{"app": "myapp"},
+		Gzip:   true,
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer lg.Close()
+	ol := objectlog.NewObjectLog(lg)
+*/
+type (
+	HTTPLogger struct {
+		entryLevelMethods
+		errorReporting
+		url     string
+		options HTTPOptions
+		mutex   sync.Mutex
+		batch   []*ObjectLogEntry
+		slots   chan struct{}
+		flushes sync.Mutex
+		stop    chan struct{}
+		closed  bool
+		err     error
+	}
+
+	// HTTPOptions configure a *HTTPLogger
+	HTTPOptions struct {
+
+		// Format of the request body, defaults to `HTTP_FORMAT_JSON`
+		Format HTTPFormat
+
+		// BatchSize is the maximum amount of messages per request, defaults to 100
+		BatchSize int
+
+		// Interval is the maximum time messages are collected before they are sent, defaults to 1 second
+		Interval time.Duration
+
+		// Gzip compresses the request body
+		Gzip bool
+
+		// Retries is the amount of retries of failed requests, defaults to 3. Negative disables retries.
+		Retries int
+
+		// Backoff is the wait time before the first retry, which is doubled for each following retry up to
+		// `MaxBackoff`. Defaults to 100 milliseconds and 10 seconds.
+		Backoff    time.Duration
+		MaxBackoff time.Duration
+
+		// MaxInFlight is the maximum amount of concurrent requests, defaults to 2. Batches are received in
+		// order only with a single request in flight.
+		MaxInFlight int
+
+		// Client sends the requests, defaults to a client with 10 seconds timeout
+		Client *http.Client
+
+		// Header is added to each request
+		Header http.Header
+
+		// Labels are the stream labels of `HTTP_FORMAT_LOKI`, the level is always added as "level"
+		Labels map[string]string
+
+		// Index is the index of `HTTP_FORMAT_ELASTICSEARCH`, defaults to "objectlog"
+		Index string
+	}
+
+	// HTTPFormat is the format of request bodies of *HTTPLogger
+	HTTPFormat string
+
+	// httpStatusError is returned for unsuccessful responses
+	httpStatusError struct {
+		url    string
+		status string
+		code   int
+	}
+)
+
+const (
+
+	// HTTP_FORMAT_JSON sends a JSON array of objects as rendered by `JSONFormatter`
+	HTTP_FORMAT_JSON HTTPFormat = "json"
+
+	// HTTP_FORMAT_NDJSON sends one JSON object per line, as rendered by `JSONFormatter`
+	HTTP_FORMAT_NDJSON HTTPFormat = "ndjson"
+
+	// HTTP_FORMAT_LOKI sends a Grafana Loki push request with one stream per level
+	HTTP_FORMAT_LOKI HTTPFormat = "loki"
+
+	// HTTP_FORMAT_ELASTICSEARCH sends an Elasticsearch bulk request
+	HTTP_FORMAT_ELASTICSEARCH HTTPFormat = "elasticsearch"
+)
+
+// NewHTTPLogger creates new *HTTPLogger sending to the URL
+func NewHTTPLogger(url string, options HTTPOptions) (*HTTPLogger, error) {
+	if err := checkHTTPFormat(options.Format); err != nil {
+		return nil, err
+	} else if options.Format == "" {
+		options.Format = HTTP_FORMAT_JSON
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+	if options.Interval <= 0 {
+		options.Interval = time.Second
+	}
+	if options.Retries == 0 {
+		options.Retries = 3
+	}
+	if options.Backoff <= 0 {
+		options.Backoff = 100 * time.Millisecond
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = 10 * time.Second
+	}
+	if options.MaxInFlight <= 0 {
+		options.MaxInFlight = 2
+	}
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if options.Index == "" {
+		options.Index = "objectlog"
+	}
+	this := &HTTPLogger{
+		url:     url,
+		options: options,
+		slots:   make(chan struct{}, options.MaxInFlight),
+		stop:    make(chan struct{}),
+	}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	go this.loop()
+	return this, nil
+}
+
+// checkHTTPFormat returns an error, if the format is not supported. Empty is the default format.
+func checkHTTPFormat(format HTTPFormat) error {
+	switch format {
+	case "", HTTP_FORMAT_JSON, HTTP_FORMAT_NDJSON, HTTP_FORMAT_LOKI, HTTP_FORMAT_ELASTICSEARCH:
+		return nil
+	}
+	return fmt.Errorf("objectlog: unsupported HTTP format %q", format)
+}
+
+// SetErrorHandler sets the handler of write failures. If nil, then the global handler is used, see
+// `ErrorHandler`.
+func (this *HTTPLogger) SetErrorHandler(handler ErrorHandler) *HTTPLogger {
+	this.setErrorHandler(handler)
+	return this
+}
+
+// LogEntry adds the entry to the current batch and sends the batch, if full. FATAL entries close the
+// logger and exit, even if the logger is already closed.
+func (this *HTTPLogger) LogEntry(entry *ObjectLogEntry) {
+	this.mutex.Lock()
+	closed := this.closed
+	var batch []*ObjectLogEntry
+	if !closed {
+		this.batch = append(this.batch, entry)
+		if len(this.batch) >= this.options.BatchSize {
+			batch, this.batch = this.batch, nil
+		}
+	}
+	this.mutex.Unlock()
+	if batch != nil {
+		this.dispatch(batch)
+	}
+	if entry.Level == OBJECT_LOG_LEVEL_FATAL {
+		this.Close()
+		os.Exit(1)
+	}
+}
+
+// WriteEntry sends the entry immediately in a request of its own, including retries, and returns the
+// error of the last attempt
+func (this *HTTPLogger) WriteEntry(entry *ObjectLogEntry) error {
+	this.slots <- struct{}{}
+	defer func() { <-this.slots }()
+	return this.send([]*ObjectLogEntry{entry})
+}
+
+// Flush sends the current batch and waits until all requests are finished. It returns the last error of
+// a request since the previous flush.
+func (this *HTTPLogger) Flush() error {
+	this.mutex.Lock()
+	batch := this.batch
+	this.batch = nil
+	this.mutex.Unlock()
+	if len(batch) > 0 {
+		this.dispatch(batch)
+	}
+	// acquiring all slots waits for the requests in flight, concurrent flushes would share the slots
+	this.flushes.Lock()
+	defer this.flushes.Unlock()
+	for i := 0; i < cap(this.slots); i++ {
+		this.slots <- struct{}{}
+	}
+	for i := 0; i < cap(this.slots); i++ {
+		<-this.slots
+	}
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	err := this.err
+	this.err = nil
+	return err
+}
+
+// Close flushes all messages and stops the logger. Messages written after closing are dropped.
+func (this *HTTPLogger) Close() error {
+	this.mutex.Lock()
+	if !this.closed {
+		this.closed = true
+		close(this.stop)
+	}
+	this.mutex.Unlock()
+	return this.Flush()
+}
+
+func (this *HTTPLogger) loop() {
+	ticker := time.NewTicker(this.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-this.stop:
+			return
+		case <-ticker.C:
+			this.mutex.Lock()
+			batch := this.batch
+			this.batch = nil
+			this.mutex.Unlock()
+			if len(batch) > 0 {
+				this.dispatch(batch)
+			}
+		}
+	}
+}
+
+// dispatch sends the batch in the background, after waiting for a free slot
+func (this *HTTPLogger) dispatch(batch []*ObjectLogEntry) {
+	this.slots <- struct{}{}
+	go func() {
+		defer func() { <-this.slots }()
+		if err := this.send(batch); err != nil {
+			this.report(this, err)
+			this.mutex.Lock()
+			this.err = err
+			this.mutex.Unlock()
+		}
+	}()
+}
+
+func (this *HTTPLogger) send(batch []*ObjectLogEntry) error {
+	body, contentType, err := this.encode(batch)
+	if err != nil {
+		return err
+	}
+	backoff := this.options.Backoff
+	for attempt := 0; ; attempt++ {
+		err = this.post(body, contentType)
+		if err == nil {
+			return nil
+		} else if serr, ok := err.(*httpStatusError); ok && !serr.retryable() {
+			return err
+		} else if attempt >= this.options.Retries {
+			return err
+		}
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > this.options.MaxBackoff {
+			backoff = this.options.MaxBackoff
+		}
+	}
+}
+
+func (this *HTTPLogger) post(body []byte, contentType string) error {
+	req, err := http.NewRequest("POST", this.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range this.options.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", contentType)
+	if this.options.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	res, err := this.options.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return &httpStatusError{this.url, res.Status, res.StatusCode}
+	}
+	return nil
+}
+
+// encode renders the body in the configured format and compresses it, if enabled
+func (this *HTTPLogger) encode(batch []*ObjectLogEntry) ([]byte, string, error) {
+	buf := &bytes.Buffer{}
+	contentType := "application/json"
+	switch this.options.Format {
+	case HTTP_FORMAT_JSON:
+		buf.WriteByte('[')
+		for i, entry := range batch {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(httpRecord(entry))
+		}
+		buf.WriteByte(']')
+	case HTTP_FORMAT_NDJSON:
+		contentType = "application/x-ndjson"
+		for _, entry := range batch {
+			buf.Write(httpRecord(entry))
+			buf.WriteByte('\n')
+		}
+	case HTTP_FORMAT_ELASTICSEARCH:
+		contentType = "application/x-ndjson"
+		action, _ := json.Marshal(map[string]interface{}{"index": map[string]string{"_index": this.options.Index}})
+		for _, entry := range batch {
+			buf.Write(action)
+			buf.WriteByte('\n')
+			buf.Write(httpRecord(entry))
+			buf.WriteByte('\n')
+		}
+	case HTTP_FORMAT_LOKI:
+		if err := json.NewEncoder(buf).Encode(this.lokiPush(batch)); err != nil {
+			return nil, "", err
+		}
+	}
+	if !this.options.Gzip {
+		return buf.Bytes(), contentType, nil
+	}
+	compressed := &bytes.Buffer{}
+	writer := gzip.NewWriter(compressed)
+	if _, err := writer.Write(buf.Bytes()); err != nil {
+		return nil, "", err
+	} else if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return compressed.Bytes(), contentType, nil
+}
+
+// lokiPush groups the entries by level into streams
+func (this *HTTPLogger) lokiPush(batch []*ObjectLogEntry) map[string]interface{} {
+	streams := []map[string]interface{}{}
+	byLevel := map[ObjectLogLevel]int{}
+	for _, entry := range batch {
+		index, ok := byLevel[entry.Level]
+		if !ok {
+			labels := map[string]string{}
+			for key, value := range this.options.Labels {
+				labels[key] = value
+			}
+			labels["level"] = string(entry.Level)
+			index = len(streams)
+			byLevel[entry.Level] = index
+			streams = append(streams, map[string]interface{}{"stream": labels, "values": [][]string{}})
+		}
+		streams[index]["values"] = append(streams[index]["values"].([][]string), []string{
+			strconv.FormatInt(entry.Time.UnixNano(), 10),
+			string(httpRecord(entry)),
+		})
+	}
+	return map[string]interface{}{"streams": streams}
+}
+
+func httpRecord(entry *ObjectLogEntry) []byte {
+	return jsonRecord(entry.Time, entry.Level, entry.Prefix, entry.Suffix, entry.Text(), entry.LogArgs())
+}
+
+func (this *httpStatusError) Error() string {
+	return fmt.Sprintf("objectlog: POST %s: %s", this.url, this.status)
+}
+
+// retryable returns whether the request should be retried: on server errors and too many requests
+func (this *httpStatusError) retryable() bool {
+	return this.code >= 500 || this.code == http.StatusTooManyRequests
+}
@@ -0,0 +1,338 @@
+package objectlog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testHTTPServer records the bodies of all successful requests and lets the handler decide the status
+type testHTTPServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []*http.Request
+	bodies   []string
+	status   func(attempt int) int
+}
+
+func newTestHTTPServer(status func(attempt int) int) *testHTTPServer {
+	this := &testHTTPServer{status: status}
+	this.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			reader, _ = gzip.NewReader(r.Body)
+		}
+		body, _ := ioutil.ReadAll(reader)
+		this.mutex.Lock()
+		this.requests = append(this.requests, r)
+		attempt, status := len(this.requests), this.status
+		this.mutex.Unlock()
+		code := http.StatusNoContent
+		if status != nil {
+			code = status(attempt)
+		}
+		if code < 300 {
+			this.mutex.Lock()
+			this.bodies = append(this.bodies, string(body))
+			this.mutex.Unlock()
+		}
+		w.WriteHeader(code)
+	}))
+	return this
+}
+
+func (this *testHTTPServer) Bodies() []string {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return append([]string{}, this.bodies...)
+}
+
+func (this *testHTTPServer) Requests() int {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return len(this.requests)
+}
+
+func testHTTPMessages(t *testing.T, body string) []string {
+	records := []map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal([]byte(body), &records), body)
+	messages := []string{}
+	for _, record := range records {
+		messages = append(messages, record["msg"].(string))
+	}
+	return messages
+}
+
+func TestHTTPLogger_ArgsSnapshot(t *testing.T) {
+	server := newTestHTTPServer(nil)
+	defer server.Close()
+	lg, err := NewHTTPLogger(server.URL, HTTPOptions{Interval: time.Hour})
+	if !assert.Nil(t, err) {
+		return
+	}
+	ol := NewObjectLog(lg).SetLogArg("state", "one")
+	ol.LogInfo("first")
+	ol.SetLogArg("state", "two")
+	assert.Nil(t, lg.Close())
+	bodies := server.Bodies()
+	if assert.Len(t, bodies, 1) {
+		records := []map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal([]byte(bodies[0]), &records))
+		if assert.Len(t, records, 1) {
+			assert.Equal(t, "one", records[0]["state"])
+		}
+	}
+}
+
+func TestHTTPLogger_BatchSize(t *testing.T) {
+	server := newTestHTTPServer(nil)
+	defer server.Close()
+	lg, err := NewHTTPLogger(server.URL, HTTPOptions{BatchSize: 2, Interval: time.Hour, MaxInFlight: 1})
+	if !assert.Nil(t, err) {
+		return
+	}
+	ol := NewObjectLog(lg).SetLogArg("foo", "bar")
+	ol.LogInfo("one")
+	ol.LogWarn("two")
+	ol.LogError("three")
+	assert.Nil(t, lg.Flush())
+
+	bodies := server.Bodies()
+	if assert.Len(t, bodies, 2) {
+		assert.Equal(t, []string{"one", "two"}, testHTTPMessages(t, bodies[0]))
+		assert.Equal(t, []string{"three"}, testHTTPMessages(t, bodies[1]))
+		assert.Contains(t, bodies[0], `"foo":"bar"`)
+		assert.Contains(t, bodies[0], `"level":"warn"`)
+	}
+	assert.Equal(t, "application/json", server.requests[0].Header.Get("Content-Type"))
+	assert.Nil(t, lg.Close())
+}
+
+func TestHTTPLogger_Interval(t *testing.T) {
+	server := newTestHTTPServer(nil)
+	defer server.Close()
+	lg, err := NewHTTPLogger(server.URL, HTTPOptions{Interval: 20 * time.Millisecond, Gzip: true})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer lg.Close()
+	NewObjectLog(lg).LogInfo("hello")
+	deadline := time.Now().Add(2 * time.Second)
+	for len(server.Bodies()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if bodies := server.Bodies(); assert.Len(t, bodies, 1) {
+		assert.Equal(t, []string{"hello"}, testHTTPMessages(t, bodies[0]))
+	}
+}
+
+func TestHTTPLogger_Formats(t *testing.T) {
+	server := newTestHTTPServer(nil)
+	defer server.Close()
+	for _, format := range []HTTPFormat{HTTP_FORMAT_NDJSON, HTTP_FORMAT_ELASTICSEARCH, HTTP_FORMAT_LOKI} {
+		lg, err := NewHTTPLogger(server.URL, HTTPOptions{
+			Format: format,
+			Labels: map[string]string{"app": "test"},
+			Index:  "logs",
+			Header: http.Header{"Authorization": []string{"Bearer x"}},
+		})
+		if !assert.Nil(t, err) {
+			continue
+		}
+		ol := NewObjectLog(lg)
+		ol.LogInfo("one")
+		ol.LogWarn("two")
+		ol.LogInfo("three")
+		assert.Nil(t, lg.Close())
+	}
+	bodies := server.Bodies()
+	if !assert.Len(t, bodies, 3) {
+		return
+	}
+	assert.Equal(t, "Bearer x", server.requests[0].Header.Get("Authorization"))
+
+	assert.Equal(t, "application/x-ndjson", server.requests[0].Header.Get("Content-Type"))
+	lines := []string{}
+	scanner := bufio.NewScanner(strings.NewReader(bodies[0]))
+	for scanner.Scan() {
+		record := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &record))
+		lines = append(lines, record["msg"].(string))
+	}
+	assert.Equal(t, []string{"one", "two", "three"}, lines)
+
+	es := strings.Split(strings.TrimSpace(bodies[1]), "\n")
+	if assert.Len(t, es, 6) {
+		assert.Equal(t, `{"index":{"_index":"logs"}}`, es[0])
+		assert.Contains(t, es[1], `"msg":"one"`)
+		assert.Contains(t, es[5], `"msg":"three"`)
+	}
+
+	push := struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][]string        `json:"values"`
+		} `json:"streams"`
+	}{}
+	if assert.Nil(t, json.Unmarshal([]byte(bodies[2]), &push)) && assert.Len(t, push.Streams, 2) {
+		assert.Equal(t, map[string]string{"app": "test", "level": "info"}, push.Streams[0].Stream)
+		assert.Equal(t, map[string]string{"app": "test", "level": "warn"}, push.Streams[1].Stream)
+		if assert.Len(t, push.Streams[0].Values, 2) {
+			assert.Regexp(t, `^\d+$`, push.Streams[0].Values[0][0])
+			assert.Contains(t, push.Streams[0].Values[1][1], `"msg":"three"`)
+		}
+	}
+}
+
+func TestHTTPLogger_Retries(t *testing.T) {
+	server := newTestHTTPServer(func(attempt int) int {
+		if attempt < 3 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	})
+	defer server.Close()
+	lg, err := NewHTTPLogger(server.URL, HTTPOptions{Backoff: time.Millisecond})
+	if !assert.Nil(t, err) {
+		return
+	}
+	NewObjectLog(lg).LogInfo("hello")
+	assert.Nil(t, lg.Close())
+	assert.Equal(t, 3, server.Requests())
+	assert.Len(t, server.Bodies(), 1)
+}
+
+func TestHTTPLogger_Failures(t *testing.T) {
+	server := newTestHTTPServer(func(attempt int) int {
+		return http.StatusInternalServerError
+	})
+	defer server.Close()
+	lg, err := NewHTTPLogger(server.URL, HTTPOptions{Backoff: time.Millisecond, Retries: 2})
+	if !assert.Nil(t, err) {
+		return
+	}
+	handler := &testErrorHandler{}
+	lg.SetErrorHandler(handler.Handle)
+	NewObjectLog(lg).LogInfo("hello")
+	err = lg.Flush()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "objectlog: POST "+server.URL+": 500 Internal Server Error", err.Error())
+	}
+	assert.Equal(t, 3, server.Requests())
+	assert.Nil(t, lg.Flush())
+
+	server.mutex.Lock()
+	server.status = func(attempt int) int { return http.StatusBadRequest }
+	server.mutex.Unlock()
+	NewObjectLog(lg).LogInfo("hello")
+	assert.NotNil(t, lg.Close())
+	assert.Equal(t, 4, server.Requests(), "client errors are not retried")
+	assert.Equal(t, []string{
+		"objectlog: POST " + server.URL + ": 500 Internal Server Error",
+		"objectlog: POST " + server.URL + ": 400 Bad Request",
+	}, handler.errors)
+	assert.Equal(t, uint64(2), lg.Failures())
+}
+
+func TestHTTPLogger_ConcurrentFlush(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+	lg, err := NewHTTPLogger(server.URL, HTTPOptions{BatchSize: 1, MaxInFlight: 4, Interval: time.Hour})
+	if !assert.Nil(t, err) {
+		return
+	}
+	ol := NewObjectLog(lg)
+	for i := 0; i < 4; i++ {
+		ol.LogInfo("hello")
+	}
+	done := make(chan struct{})
+	var flushes sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		flushes.Add(1)
+		go func() {
+			defer flushes.Done()
+			lg.Flush()
+		}()
+	}
+	go func() {
+		flushes.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("concurrent flushes did not return")
+	}
+	assert.Nil(t, lg.Close())
+}
+
+func TestHTTPLogger_MaxInFlight(t *testing.T) {
+	var current, max int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&current, -1)
+	}))
+	defer server.Close()
+	lg, err := NewHTTPLogger(server.URL, HTTPOptions{BatchSize: 1, MaxInFlight: 2, Interval: time.Hour})
+	if !assert.Nil(t, err) {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		ol := NewObjectLog(lg)
+		for i := 0; i < 5; i++ {
+			ol.LogInfo("hello")
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("writing should block while maximum of requests is in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-done
+	assert.Nil(t, lg.Close())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&max))
+}
+
+func TestHTTPLogger_Closed(t *testing.T) {
+	server := newTestHTTPServer(nil)
+	defer server.Close()
+	lg, err := NewHTTPLogger(server.URL, HTTPOptions{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, lg.Close())
+	assert.Nil(t, lg.Close())
+	NewObjectLog(lg).LogInfo("dropped")
+	assert.Nil(t, lg.Flush())
+	assert.Equal(t, 0, server.Requests())
+}
+
+func TestNewHTTPLogger_Errors(t *testing.T) {
+	_, err := NewHTTPLogger("http://localhost", HTTPOptions{Format: "xml"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `objectlog: unsupported HTTP format "xml"`, err.Error())
+	}
+}
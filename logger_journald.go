@@ -0,0 +1,309 @@
+//go:build linux
+// +build linux
+
+package objectlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+/*
+JournaldLogger writes messages with the native protocol to the systemd journal, so that prefix, suffix and log
+arguments are stored as fields. Log arguments are stored with upper case names, e.g. the argument "user.id" as
+field "USER_ID".
+
+	lg, err := objectlog.NewJournaldLogger(objectlog.JournaldOptions{Identifier: "myapp"})
+	if err != nil {
+		panic(err)
+	}
+	defer lg.Close()
+	ol := objectlog.NewObjectLog(lg)
+
+The fields can be queried with `journalctl`:
+
+	journalctl -t myapp USER_ID=123 -o verbose
+*/
+type (
+	JournaldLogger struct {
+		entryLevelMethods
+		errorReporting
+		conn    *net.UnixConn
+		socket  *net.UnixAddr
+		options JournaldOptions
+	}
+
+	// JournaldOptions configure a *JournaldLogger
+	JournaldOptions struct {
+
+		// Socket is the path of the journal socket, defaults to `JOURNALD_SOCKET`
+		Socket string
+
+		// Identifier is written as SYSLOG_IDENTIFIER, defaults to the name of the executable
+		Identifier string
+
+		// Fields are added to every message, names must be valid journal field names
+		Fields map[string]string
+	}
+)
+
+const (
+
+	// JOURNALD_SOCKET is the default path of the socket of the journal
+	JOURNALD_SOCKET = "/run/systemd/journal/socket"
+
+	// flags of memfd_create and fcntl, which are not provided by package syscall
+	journaldMfdCloexec      = 0x1
+	journaldMfdAllowSealing = 0x2
+	journaldAddSeals        = 0x409
+	journaldSeals           = 0x1 | 0x2 | 0x4 | 0x8 // seal, shrink, grow and write
+)
+
+var (
+
+	// journaldReserved are the fields written by the logger, which log arguments must not overwrite
+	journaldReserved = map[string]bool{
+		"MESSAGE":           true,
+		"PRIORITY":          true,
+		"SYSLOG_IDENTIFIER": true,
+	}
+)
+
+// NewJournaldLogger creates new *JournaldLogger
+func NewJournaldLogger(options JournaldOptions) (*JournaldLogger, error) {
+	if options.Socket == "" {
+		options.Socket = JOURNALD_SOCKET
+	}
+	if options.Identifier == "" {
+		options.Identifier = filepath.Base(os.Args[0])
+	}
+	if _, err := os.Stat(options.Socket); err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	this := &JournaldLogger{
+		conn:    conn,
+		socket:  &net.UnixAddr{Name: options.Socket, Net: "unixgram"},
+		options: options,
+	}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	return this, nil
+}
+
+// SetErrorHandler sets the handler of write failures. If nil, then the global handler is used, see
+// `ErrorHandler`.
+func (this *JournaldLogger) SetErrorHandler(handler ErrorHandler) *JournaldLogger {
+	this.setErrorHandler(handler)
+	return this
+}
+
+// LogEntry writes the entry. FATAL entries exit after writing.
+func (this *JournaldLogger) LogEntry(entry *ObjectLogEntry) {
+	this.report(this, this.WriteEntry(entry))
+	if entry.Level == OBJECT_LOG_LEVEL_FATAL {
+		os.Exit(1)
+	}
+}
+
+// WriteEntry writes the entry as datagram to the journal. If the entry is too large for a datagram, it is
+// written to a memory backed file, of which the descriptor is passed to the journal.
+func (this *JournaldLogger) WriteEntry(entry *ObjectLogEntry) error {
+	data := this.encode(entry)
+	_, _, err := this.conn.WriteMsgUnix(data, nil, this.socket)
+	if err == nil {
+		return nil
+	} else if !journaldTooLarge(err) {
+		return err
+	}
+	return this.writeFile(data)
+}
+
+// Close closes the socket
+func (this *JournaldLogger) Close() error {
+	return this.conn.Close()
+}
+
+// encode renders the entry as fields of the native journal protocol
+func (this *JournaldLogger) encode(entry *ObjectLogEntry) []byte {
+	buf := &bytes.Buffer{}
+	journaldField(buf, "MESSAGE", entry.Prefix+entry.Text()+entry.Suffix)
+	journaldField(buf, "PRIORITY", strconv.Itoa(SyslogSeverity(entry.Level)))
+	journaldField(buf, "SYSLOG_IDENTIFIER", this.options.Identifier)
+	journaldField(buf, "OBJECTLOG_LEVEL", string(entry.Level))
+	if prefix := strings.TrimSpace(entry.Prefix); prefix != "" {
+		journaldField(buf, "OBJECTLOG_PREFIX", prefix)
+	}
+	if suffix := strings.TrimSpace(entry.Suffix); suffix != "" {
+		journaldField(buf, "OBJECTLOG_SUFFIX", suffix)
+	}
+	for name, value := range this.options.Fields {
+		journaldField(buf, name, value)
+	}
+	for key, value := range entry.LogArgs() {
+		name := JournaldFieldName(key)
+		if _, ok := this.options.Fields[name]; ok {
+			name = JournaldFieldName("ARG_" + name)
+		}
+		journaldField(buf, name, journaldValue(value))
+	}
+	return buf.Bytes()
+}
+
+// writeFile passes the data in a sealed memory file to the journal. If the kernel does not support sealed
+// memory files, an unlinked file in shared memory is passed instead.
+func (this *JournaldLogger) writeFile(data []byte) error {
+	fh, err := journaldMemfd(data)
+	if err != nil {
+		if fh, err = journaldShmFile(data); err != nil {
+			return err
+		}
+	}
+	defer fh.Close()
+	_, _, err = this.conn.WriteMsgUnix(nil, syscall.UnixRights(int(fh.Fd())), this.socket)
+	return err
+}
+
+// journaldMemfd writes the data to an anonymous memory file, which is sealed against writing, growing and
+// shrinking as expected by the journal
+func journaldMemfd(data []byte) (*os.File, error) {
+	trap := journaldMemfdCreate()
+	if trap == 0 {
+		return nil, fmt.Errorf("objectlog: memfd_create is not supported on %s", runtime.GOARCH)
+	}
+	name, err := syscall.BytePtrFromString("objectlog-journal")
+	if err != nil {
+		return nil, err
+	}
+	fd, _, errno := syscall.Syscall(trap, uintptr(unsafe.Pointer(name)), journaldMfdCloexec|journaldMfdAllowSealing, 0)
+	if errno != 0 {
+		return nil, errno
+	}
+	fh := os.NewFile(fd, "objectlog-journal")
+	if _, err = fh.Write(data); err == nil {
+		if _, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fh.Fd(), journaldAddSeals, journaldSeals); errno != 0 {
+			err = errno
+		}
+	}
+	if err != nil {
+		fh.Close()
+		return nil, err
+	}
+	return fh, nil
+}
+
+// journaldMemfdCreate returns the number of the memfd_create system call, which package syscall does not
+// provide for most architectures, or zero if it is unknown
+func journaldMemfdCreate() uintptr {
+	switch runtime.GOARCH {
+	case "amd64":
+		return 319
+	case "386":
+		return 356
+	case "arm":
+		return 385
+	case "arm64", "loong64", "riscv64":
+		return 279
+	case "mips", "mipsle":
+		return 4354
+	case "mips64", "mips64le":
+		return 5314
+	case "ppc", "ppc64", "ppc64le":
+		return 360
+	case "s390x":
+		return 350
+	case "sparc64":
+		return 348
+	}
+	return 0
+}
+
+// journaldShmFile writes the data to an unlinked file in /dev/shm
+func journaldShmFile(data []byte) (*os.File, error) {
+	fh, err := ioutil.TempFile("/dev/shm", "objectlog-journal")
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(fh.Name())
+	if _, err := fh.Write(data); err != nil {
+		fh.Close()
+		return nil, err
+	}
+	return fh, nil
+}
+
+// JournaldFieldName converts a log argument name into a journal field name: Upper case letters, digits and
+// underscores, not starting with an underscore or digit and at most 64 characters. Names of the fields written
+// by the logger, like "MESSAGE" or "OBJECTLOG_LEVEL", are prefixed with "ARG_".
+func JournaldFieldName(name string) string {
+	field := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+	field = strings.TrimLeft(field, "_")
+	if field == "" || field[0] >= '0' && field[0] <= '9' || journaldReserved[field] || strings.HasPrefix(field, "OBJECTLOG_") {
+		field = "ARG_" + field
+	}
+	if len(field) > 64 {
+		field = field[:64]
+	}
+	return field
+}
+
+// journaldField writes a field, values containing new lines are written with their length
+func journaldField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if strings.IndexByte(value, '\n') < 0 {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+	} else {
+		buf.WriteByte('\n')
+		binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+		buf.WriteString(value)
+	}
+	buf.WriteByte('\n')
+}
+
+func journaldValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	if raw, err := json.Marshal(value); err == nil {
+		return string(raw)
+	}
+	return fmt.Sprint(value)
+}
+
+// journaldTooLarge returns whether the error indicates a datagram exceeding the size limit
+func journaldTooLarge(err error) bool {
+	if operr, ok := err.(*net.OpError); ok {
+		err = operr.Err
+	}
+	if syserr, ok := err.(*os.SyscallError); ok {
+		err = syserr.Err
+	}
+	return err == syscall.EMSGSIZE || err == syscall.ENOBUFS
+}
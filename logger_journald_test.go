@@ -0,0 +1,155 @@
+//go:build linux
+// +build linux
+
+package objectlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// testJournaldSocket is a stand-in for the journal socket, which decodes the received fields
+func testJournaldSocket(t *testing.T) (string, *net.UnixConn, func()) {
+	dir, err := ioutil.TempDir("", "objectlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, conn, func() {
+		conn.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func testJournaldReceive(t *testing.T, conn *net.UnixConn) map[string]string {
+	buf := make([]byte, 1<<20)
+	oob := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	data := buf[:n]
+	if oobn > 0 {
+		messages, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		assert.Nil(t, err)
+		fds, err := syscall.ParseUnixRights(&messages[0])
+		assert.Nil(t, err)
+		fh := os.NewFile(uintptr(fds[0]), "journal")
+		defer fh.Close()
+		fh.Seek(0, 0)
+		data, err = ioutil.ReadAll(fh)
+		assert.Nil(t, err)
+	}
+	fields := map[string]string{}
+	for len(data) > 0 {
+		nl := bytes.IndexByte(data, '\n')
+		line := string(data[:nl])
+		data = data[nl+1:]
+		if eq := strings.IndexByte(line, '='); eq >= 0 {
+			fields[line[:eq]] = line[eq+1:]
+			continue
+		}
+		size := binary.LittleEndian.Uint64(data[:8])
+		fields[line] = string(data[8 : 8+size])
+		data = data[8+size+1:]
+	}
+	return fields
+}
+
+func TestJournaldLogger(t *testing.T) {
+	path, conn, cleanup := testJournaldSocket(t)
+	defer cleanup()
+	lg, err := NewJournaldLogger(JournaldOptions{
+		Socket:     path,
+		Identifier: "myapp",
+		Fields:     map[string]string{"ENV": "test"},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer lg.Close()
+	ol := NewObjectLog(lg).SetLogPrefix("db: ").SetLogArgs(map[string]interface{}{
+		"user.id": 123,
+		"query":   "SELECT 1\nFROM dual",
+		"message": "reserved",
+		"env":     "arg",
+	})
+	ol.LogWarn("slow %s", "query")
+	assert.Equal(t, map[string]string{
+		"MESSAGE":           "db: slow query",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "myapp",
+		"OBJECTLOG_LEVEL":   "warn",
+		"OBJECTLOG_PREFIX":  "db:",
+		"ENV":               "test",
+		"USER_ID":           "123",
+		"QUERY":             "SELECT 1\nFROM dual",
+		"ARG_MESSAGE":       "reserved",
+		"ARG_ENV":           "arg",
+	}, testJournaldReceive(t, conn))
+}
+
+func TestJournaldLogger_Large(t *testing.T) {
+	path, conn, cleanup := testJournaldSocket(t)
+	defer cleanup()
+	lg, err := NewJournaldLogger(JournaldOptions{Socket: path})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer lg.Close()
+	large := strings.Repeat("x", 512*1024)
+	assert.Nil(t, lg.WriteEntry(NewTextEntry(OBJECT_LOG_LEVEL_ERROR, large)))
+	fields := testJournaldReceive(t, conn)
+	assert.Equal(t, large, fields["MESSAGE"])
+	assert.Equal(t, "3", fields["PRIORITY"])
+	assert.Equal(t, filepath.Base(os.Args[0]), fields["SYSLOG_IDENTIFIER"])
+}
+
+func TestJournaldMemfd(t *testing.T) {
+	fh, err := journaldMemfd([]byte("MESSAGE=hello\n"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer fh.Close()
+	seals, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fh.Fd(), 0x40a, 0) // F_GET_SEALS
+	assert.Equal(t, syscall.Errno(0), errno)
+	assert.Equal(t, uintptr(journaldSeals), seals)
+	_, err = fh.WriteAt([]byte("x"), 0)
+	assert.NotNil(t, err, "sealed against writing")
+}
+
+func TestJournaldFieldName(t *testing.T) {
+	for name, expect := range map[string]string{
+		"foo":                   "FOO",
+		"user.id":               "USER_ID",
+		"_private":              "PRIVATE",
+		"1st":                   "ARG_1ST",
+		"message":               "ARG_MESSAGE",
+		"Priority":              "ARG_PRIORITY",
+		"syslog.identifier":     "ARG_SYSLOG_IDENTIFIER",
+		"objectlog_level":       "ARG_OBJECTLOG_LEVEL",
+		"":                      "ARG_",
+		strings.Repeat("a", 70): strings.Repeat("A", 64),
+	} {
+		assert.Equal(t, expect, JournaldFieldName(name), name)
+	}
+}
+
+func TestNewJournaldLogger_Missing(t *testing.T) {
+	_, err := NewJournaldLogger(JournaldOptions{Socket: "/does/not/exist"})
+	assert.NotNil(t, err)
+}
@@ -1,5 +1,12 @@
 package objectlog
 
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
 /*
 MultiLogger broadcasts messages to multiple loggers, in case one is not sufficient. For example:
 Log to local syslog and remote syslog and STDERR ..
@@ -9,11 +16,54 @@ Log to local syslog and remote syslog and STDERR ..
 	log3 := NewYourLogger()
 	mult := objectlog.NewMultiLogger(log1, log2, log3)
 	mult.LogDebug("Hello all") // writes to all three logers
+
+A logger which panics does not abort the broadcast: the panic is recovered and reported to the error handler.
+In parallel mode, each logger writes in a goroutine of its own, so that a slow logger does not delay the
+others. Each logger still receives the messages in order. Without timeout, messages are only queued and the
+call returns without waiting for the loggers. With timeout, the call waits up to the timeout for the loggers
+and messages, which were not written in time, are dropped. FATAL messages are always waited for, so that the
+loggers can exit before the call returns.
+
+	mult.SetParallel(true).SetTimeout(time.Second).SetErrorHandler(func(logger objectlog.ObjectLogger, err error) {
+		fmt.Fprintf(os.Stderr, "logger %T failed: %s\n", logger, err)
+	})
+	defer mult.Close()
  */
 type (
 	MultiLogger struct {
-		loggers []ObjectLogger
+		mutex    sync.RWMutex
+		loggers  []ObjectLogger
+		workers  []*multiWorker
+		parallel bool
+		timeout  time.Duration
+		errorReporting
 	}
+
+	// multiWorker writes the messages of a single logger in parallel mode
+	multiWorker struct {
+		logger   ObjectLogger
+		queue    chan *multiJob
+		stopping chan struct{}
+		done     chan struct{}
+	}
+
+	// multiJob writes a message to the logger of a worker, unless it was dropped before
+	multiJob struct {
+		fn    func()
+		state int32
+		done  chan struct{}
+	}
+)
+
+const (
+
+	// states of a multiJob
+	multiJobQueued int32 = iota
+	multiJobRunning
+	multiJobDropped
+
+	// multiQueueSize is the amount of messages queued per logger in parallel mode
+	multiQueueSize = 1024
 )
 
 // NewMultiLogger creates new *MultiLogger instance
@@ -28,7 +78,12 @@ func NewMultiLogger(loggers ...ObjectLogger) *MultiLogger {
 
 // AddLogger adds another logger to the list
 func (this *MultiLogger) AddLogger(logger ObjectLogger) *MultiLogger {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
 	this.loggers = append(this.loggers, logger)
+	if this.parallel {
+		this.workers = append(this.workers, newMultiWorker(logger))
+	}
 	return this
 }
 
@@ -37,46 +92,276 @@ func (this *MultiLogger) SetLoggers(loggers ...ObjectLogger) *MultiLogger {
 	if loggers == nil {
 		loggers = make([]ObjectLogger, 0)
 	}
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.stopWorkers()
 	this.loggers = loggers
+	if this.parallel {
+		this.startWorkers()
+	}
+	return this
+}
+
+// RemoveLogger removes the logger from the list. Loggers are compared with `==`, or by their pointer, if
+// their type is not comparable, like maps and funcs. In parallel mode, already queued messages are still
+// written to it, before it returns.
+func (this *MultiLogger) RemoveLogger(logger ObjectLogger) *MultiLogger {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	for i := 0; i < len(this.loggers); i++ {
+		if !sameLogger(this.loggers[i], logger) {
+			continue
+		}
+		this.loggers = append(this.loggers[:i:i], this.loggers[i+1:]...)
+		if this.parallel {
+			this.workers[i].stop()
+			this.workers = append(this.workers[:i:i], this.workers[i+1:]...)
+		}
+		i--
+	}
 	return this
 }
 
 // Loggers returns all registered loggers
 func (this *MultiLogger) Loggers() []ObjectLogger {
-	return this.loggers
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+	return append([]ObjectLogger{}, this.loggers...)
+}
+
+// SetParallel enables or disables writing to all loggers in parallel
+func (this *MultiLogger) SetParallel(parallel bool) *MultiLogger {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if parallel && !this.parallel {
+		this.startWorkers()
+	} else if !parallel && this.parallel {
+		this.stopWorkers()
+	}
+	this.parallel = parallel
+	return this
+}
+
+// SetTimeout sets the maximum time waited for each logger to write a message in parallel mode. Messages,
+// which are not written in time, are dropped, which is reported to the error handler. Messages which are
+// already being written when the timeout elapses cannot be stopped, which is reported as well. Zero does
+// not wait for the loggers, but only queues the messages.
+func (this *MultiLogger) SetTimeout(timeout time.Duration) *MultiLogger {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.timeout = timeout
+	return this
+}
+
+// SetErrorHandler sets the handler, which is called when a logger panics or times out. If nil, then the
+// global handler is used, see `ErrorHandler`.
+func (this *MultiLogger) SetErrorHandler(handler ErrorHandler) *MultiLogger {
+	this.setErrorHandler(handler)
+	return this
+}
+
+// Close stops the goroutines of the parallel mode and waits until all queued messages are written.
+// Afterwards the loggers are written to sequentially.
+func (this *MultiLogger) Close() error {
+	this.SetParallel(false)
+	return nil
 }
 
 // Debug writes message to all registered loggers
 func (this *MultiLogger) Debug(msg string) {
-	for _, logger := range this.loggers {
+	this.broadcast(false, func(logger ObjectLogger) {
 		logger.Debug(msg)
-	}
+	})
 }
 
 // Info writes message to all registered loggers
 func (this *MultiLogger) Info(msg string) {
-	for _, logger := range this.loggers {
+	this.broadcast(false, func(logger ObjectLogger) {
 		logger.Info(msg)
-	}
+	})
 }
 
 // Warn writes message to all registered loggers
 func (this *MultiLogger) Warn(msg string) {
-	for _, logger := range this.loggers {
+	this.broadcast(false, func(logger ObjectLogger) {
 		logger.Warn(msg)
-	}
+	})
 }
 
 // Error writes message to all registered loggers
 func (this *MultiLogger) Error(msg string) {
-	for _, logger := range this.loggers {
+	this.broadcast(false, func(logger ObjectLogger) {
 		logger.Error(msg)
-	}
+	})
 }
 
 // Fatal writes message to all registered loggers
 func (this *MultiLogger) Fatal(msg string) {
-	for _, logger := range this.loggers {
+	this.broadcast(true, func(logger ObjectLogger) {
 		logger.Fatal(msg)
+	})
+}
+
+// Log writes message of any other level to all registered loggers
+func (this *MultiLogger) Log(level ObjectLogLevel, msg string) {
+	this.broadcast(level == OBJECT_LOG_LEVEL_FATAL, func(logger ObjectLogger) {
+		writeLevel(logger, level, msg)
+	})
+}
+
+// LogEntry writes entry to all registered loggers
+func (this *MultiLogger) LogEntry(entry *ObjectLogEntry) {
+	this.broadcast(entry.Level == OBJECT_LOG_LEVEL_FATAL, func(logger ObjectLogger) {
+		writeEntry(logger, entry)
+	})
+}
+
+// broadcast calls the function with each logger, sequentially or in the goroutines of the loggers. The
+// lock is only held to read the loggers, not while writing or waiting.
+func (this *MultiLogger) broadcast(fatal bool, fn func(logger ObjectLogger)) {
+	this.mutex.RLock()
+	parallel, loggers, workers, timeout := this.parallel, this.loggers, this.workers, this.timeout
+	this.mutex.RUnlock()
+	if !parallel {
+		for _, logger := range loggers {
+			this.call(logger, fn)
+		}
+		return
+	}
+
+	var expired chan struct{}
+	if timeout > 0 && !fatal {
+		expired = make(chan struct{})
+		timer := time.AfterFunc(timeout, func() { close(expired) })
+		defer timer.Stop()
+	}
+	jobs := make([]*multiJob, len(workers))
+	for i, worker := range workers {
+		logger := worker.logger
+		job := newMultiJob(func() {
+			this.call(logger, fn)
+		})
+		select {
+		case worker.queue <- job:
+			jobs[i] = job
+			continue
+		default:
+		}
+		select {
+		case worker.queue <- job:
+			jobs[i] = job
+		case <-worker.done:
+		case <-expired:
+			this.report(logger, fmt.Errorf("dropped message, which was not accepted within %s", timeout))
+		}
+	}
+	if timeout == 0 && !fatal {
+		return
 	}
+	for i, job := range jobs {
+		if job == nil {
+			continue
+		}
+		select {
+		case <-job.done:
+			continue
+		default:
+		}
+		select {
+		case <-job.done:
+		case <-workers[i].done:
+		case <-expired:
+			logger := workers[i].logger
+			if job.drop() {
+				this.report(logger, fmt.Errorf("dropped message, which was not written within %s", timeout))
+			} else {
+				this.report(logger, fmt.Errorf("still writing message after %s", timeout))
+			}
+		}
+	}
+}
+
+func (this *MultiLogger) startWorkers() {
+	this.workers = make([]*multiWorker, len(this.loggers))
+	for i, logger := range this.loggers {
+		this.workers[i] = newMultiWorker(logger)
+	}
+}
+
+// stopWorkers stops all workers and waits until they have written their queued messages
+func (this *MultiLogger) stopWorkers() {
+	for _, worker := range this.workers {
+		close(worker.stopping)
+	}
+	for _, worker := range this.workers {
+		<-worker.done
+	}
+	this.workers = nil
+}
+
+func newMultiWorker(logger ObjectLogger) *multiWorker {
+	worker := &multiWorker{
+		logger:   logger,
+		queue:    make(chan *multiJob, multiQueueSize),
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go worker.run()
+	return worker
+}
+
+// run writes the queued messages, until the worker is stopped and the queue is empty
+func (this *multiWorker) run() {
+	defer close(this.done)
+	for {
+		select {
+		case job := <-this.queue:
+			job.run()
+		case <-this.stopping:
+			for {
+				select {
+				case job := <-this.queue:
+					job.run()
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// stop stops the worker and waits until all queued messages are written
+func (this *multiWorker) stop() {
+	close(this.stopping)
+	<-this.done
+}
+
+func newMultiJob(fn func()) *multiJob {
+	return &multiJob{
+		fn:   fn,
+		done: make(chan struct{}),
+	}
+}
+
+// run calls the function, unless the job was dropped
+func (this *multiJob) run() {
+	if atomic.CompareAndSwapInt32(&this.state, multiJobQueued, multiJobRunning) {
+		this.fn()
+	}
+	close(this.done)
+}
+
+// drop prevents the job from running and returns true, if it has not been started yet
+func (this *multiJob) drop() bool {
+	return atomic.CompareAndSwapInt32(&this.state, multiJobQueued, multiJobDropped)
+}
+
+// call calls the function with the logger and reports a panic
+func (this *MultiLogger) call(logger ObjectLogger, fn func(logger ObjectLogger)) {
+	defer func() {
+		if r := recover(); r != nil {
+			this.report(logger, fmt.Errorf("panic: %v", r))
+		}
+	}()
+	fn(logger)
 }
@@ -2,8 +2,11 @@ package objectlog
 
 import (
 	"github.com/stretchr/testify/assert"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestMultiObjectLog(t *testing.T) {
@@ -15,13 +18,191 @@ func TestMultiObjectLog(t *testing.T) {
 	lg.Warn("From Warn")
 	lg.Error("From Error")
 	lg.Fatal("From Fatal")
+	lg.Log(OBJECT_LOG_LEVEL_TRACE, "From Trace")
 	expect := strings.Join([]string{
 		"[DBG] From Debug",
 		"[INF] From Info",
 		"[WRN] From Warn",
 		"[ERR] From Error",
 		"[FTL] From Fatal",
+		"[TRC] From Trace",
 	}, "\n") + "\n"
 	assert.Equal(t, expect, l1.Buffer().String())
 	assert.Equal(t, expect, l2.Buffer().String())
 }
+
+// testPanicLogger panics on every message
+type testPanicLogger struct {
+	entryLevelMethods
+}
+
+func newTestPanicLogger() *testPanicLogger {
+	this := &testPanicLogger{}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	return this
+}
+
+func (this *testPanicLogger) LogEntry(entry *ObjectLogEntry) {
+	panic("boom")
+}
+
+// testBlockingLogger blocks every message until released and counts the written messages
+type testBlockingLogger struct {
+	entryLevelMethods
+	release chan struct{}
+	written int32
+}
+
+func newTestBlockingLogger() *testBlockingLogger {
+	this := &testBlockingLogger{release: make(chan struct{})}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	return this
+}
+
+func (this *testBlockingLogger) LogEntry(entry *ObjectLogEntry) {
+	<-this.release
+	atomic.AddInt32(&this.written, 1)
+}
+
+func TestMultiLogger_Panic(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		buf := NewBufferObjectLog()
+		failing := newTestPanicLogger()
+		errs := []string{}
+		lg := NewMultiLogger(failing, buf).SetParallel(parallel).SetErrorHandler(func(logger ObjectLogger, err error) {
+			assert.Equal(t, failing, logger)
+			errs = append(errs, err.Error())
+		})
+		NewObjectLog(lg).LogInfo("hello")
+		lg.Warn("world")
+		assert.Nil(t, lg.Close())
+		assert.Equal(t, "[INF] hello\n[WRN] world\n", buf.Buffer().String())
+		assert.Equal(t, []string{
+			"panic: boom",
+			"panic: boom",
+		}, errs)
+		assert.Equal(t, uint64(2), lg.Failures())
+	}
+}
+
+func TestMultiLogger_ParallelOrder(t *testing.T) {
+	l1 := NewBufferObjectLog()
+	l2 := NewBufferObjectLog()
+	lg := NewMultiLogger(l1, l2).SetParallel(true)
+	ol := NewObjectLog(lg)
+	expect := ""
+	for i := 0; i < 100; i++ {
+		ol.LogInfo("message %d", i)
+		expect += "[INF] message " + strconv.Itoa(i) + "\n"
+	}
+	assert.Nil(t, lg.Close())
+	assert.Equal(t, expect, l1.Buffer().String())
+	assert.Equal(t, expect, l2.Buffer().String())
+}
+
+func TestMultiLogger_Timeout(t *testing.T) {
+	buf := NewBufferObjectLog()
+	slow := newTestBlockingLogger()
+	errs := make(chan error, 10)
+	lg := NewMultiLogger(slow, buf).SetParallel(true).SetTimeout(20 * time.Millisecond).SetErrorHandler(func(logger ObjectLogger, err error) {
+		errs <- err
+	})
+	lg.Info("first")
+	lg.Info("second")
+	assert.Equal(t, "[INF] first\n[INF] second\n", buf.Buffer().String())
+	for _, expect := range []string{
+		"still writing message after 20ms",
+		"dropped message, which was not written within 20ms",
+	} {
+		select {
+		case err := <-errs:
+			assert.Equal(t, expect, err.Error())
+		default:
+			t.Fatal("expected timeout error")
+		}
+	}
+	close(slow.release)
+	assert.Nil(t, lg.Close())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&slow.written), "close waits for queued messages, dropped are not written")
+}
+
+func TestMultiLogger_NoTimeout(t *testing.T) {
+	buf := NewBufferObjectLog()
+	slow := newTestBlockingLogger()
+	lg := NewMultiLogger(slow, buf).SetParallel(true)
+	done := make(chan struct{})
+	go func() {
+		lg.Info("first")
+		lg.Info("second")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("messages should be queued without waiting for the loggers")
+	}
+	lg.SetTimeout(time.Second)
+	close(slow.release)
+	assert.Nil(t, lg.Close())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&slow.written))
+	assert.Equal(t, "[INF] first\n[INF] second\n", buf.Buffer().String())
+}
+
+func TestMultiLogger_Fatal(t *testing.T) {
+	buf := NewBufferObjectLog()
+	slow := newTestBlockingLogger()
+	lg := NewMultiLogger(slow, buf).SetParallel(true).SetTimeout(10 * time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		lg.Fatal("bye")
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("FATAL messages should be waited for without timeout")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(slow.release)
+	<-done
+	assert.Equal(t, int32(1), atomic.LoadInt32(&slow.written))
+	assert.Equal(t, "[FTL] bye\n", buf.Buffer().String())
+	assert.Nil(t, lg.Close())
+}
+
+// testFuncLogger is a logger of a type, which is not comparable
+type testFuncLogger func(msg string)
+
+func (this testFuncLogger) Debug(msg string) { this(msg) }
+func (this testFuncLogger) Info(msg string)  { this(msg) }
+func (this testFuncLogger) Warn(msg string)  { this(msg) }
+func (this testFuncLogger) Error(msg string) { this(msg) }
+func (this testFuncLogger) Fatal(msg string) { this(msg) }
+
+func TestMultiLogger_RemoveLogger_Uncomparable(t *testing.T) {
+	messages := []string{}
+	l1 := testFuncLogger(func(msg string) { messages = append(messages, msg) })
+	l2 := testFuncLogger(func(msg string) {})
+	lg := NewMultiLogger(l1, l2)
+	lg.Info("both")
+	lg.RemoveLogger(l1)
+	lg.Info("second")
+	assert.Equal(t, []string{"both"}, messages)
+	assert.Len(t, lg.Loggers(), 1)
+}
+
+func TestMultiLogger_RemoveLogger(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		l1 := NewBufferObjectLog()
+		l2 := NewBufferObjectLog()
+		lg := NewMultiLogger(l1, l2).SetParallel(parallel)
+		lg.Info("both")
+		lg.RemoveLogger(l1)
+		assert.Equal(t, []ObjectLogger{l2}, lg.Loggers())
+		lg.Info("second")
+		lg.AddLogger(l1)
+		lg.Info("again")
+		assert.Nil(t, lg.Close())
+		assert.Equal(t, "[INF] both\n[INF] again\n", l1.Buffer().String())
+		assert.Equal(t, "[INF] both\n[INF] second\n[INF] again\n", l2.Buffer().String())
+	}
+}
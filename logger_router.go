@@ -0,0 +1,120 @@
+package objectlog
+
+import (
+	"reflect"
+	"sync"
+)
+
+/*
+RouterLogger writes messages only to the loggers of the routes, which accept them. A route consists of a filter
+and the loggers, messages accepted by the filter are written to. Messages accepted by multiple routes are written
+once to each logger. Messages not accepted by any route are written to the default loggers.
+
+	alert := NewYourAlertLogger()
+	file := objectlog.NewStandardLogger(log.New(fh, "", log.LstdFlags))
+	router := objectlog.NewRouterLogger().
+		AddLevelRoute(objectlog.OBJECT_LOG_LEVEL_DEBUG, objectlog.OBJECT_LOG_LEVEL_INFO, stdout).
+		AddLevelRoute(objectlog.OBJECT_LOG_LEVEL_WARN, "", stderr, file).
+		AddRoute(objectlog.LevelsFilter(objectlog.OBJECT_LOG_LEVEL_FATAL), alert).
+		AddRoute(objectlog.ArgFilter("audit", true), file)
+*/
+type (
+	RouterLogger struct {
+		entryLevelMethods
+		mutex    sync.RWMutex
+		routes   []routerRoute
+		defaults []ObjectLogger
+	}
+
+	routerRoute struct {
+		filter  ObjectLogFilter
+		loggers []ObjectLogger
+	}
+)
+
+// NewRouterLogger creates new *RouterLogger, which writes messages not accepted by any route to the
+// default loggers
+func NewRouterLogger(defaults ...ObjectLogger) *RouterLogger {
+	this := &RouterLogger{
+		defaults: defaults,
+	}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	return this
+}
+
+// AddRoute adds a route, writing all messages accepted by the filter to the loggers
+func (this *RouterLogger) AddRoute(filter ObjectLogFilter, loggers ...ObjectLogger) *RouterLogger {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.routes = append(this.routes, routerRoute{filter, loggers})
+	return this
+}
+
+// AddLevelRoute adds a route, writing all messages from the min up to including the max level to the
+// loggers. If max is empty, there is no upper bound.
+func (this *RouterLogger) AddLevelRoute(min, max ObjectLogLevel, loggers ...ObjectLogger) *RouterLogger {
+	return this.AddRoute(LevelRangeFilter(min, max), loggers...)
+}
+
+// SetDefault replaces the loggers, which receive messages not accepted by any route
+func (this *RouterLogger) SetDefault(loggers ...ObjectLogger) *RouterLogger {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.defaults = loggers
+	return this
+}
+
+// Route returns the loggers the entry would be written to
+func (this *RouterLogger) Route(entry *ObjectLogEntry) []ObjectLogger {
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+	matched := false
+	loggers := []ObjectLogger{}
+	for _, route := range this.routes {
+		if !route.filter(entry) {
+			continue
+		}
+		matched = true
+		for _, logger := range route.loggers {
+			if !containsLogger(loggers, logger) {
+				loggers = append(loggers, logger)
+			}
+		}
+	}
+	if !matched {
+		return append(loggers, this.defaults...)
+	}
+	return loggers
+}
+
+// LogEntry writes the entry to the loggers of all accepting routes, or to the default loggers
+func (this *RouterLogger) LogEntry(entry *ObjectLogEntry) {
+	for _, logger := range this.Route(entry) {
+		writeEntry(logger, entry)
+	}
+}
+
+// containsLogger returns whether the list contains the logger, see `sameLogger`
+func containsLogger(loggers []ObjectLogger, logger ObjectLogger) bool {
+	for _, l := range loggers {
+		if sameLogger(l, logger) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameLogger returns whether both loggers are the same. Loggers of comparable types are compared with `==`,
+// loggers of types like maps and funcs by their pointer. Other loggers are never considered equal.
+func sameLogger(a, b ObjectLogger) bool {
+	if a == nil || b == nil || reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	} else if reflect.TypeOf(a).Comparable() {
+		return a == b
+	}
+	switch va, vb := reflect.ValueOf(a), reflect.ValueOf(b); va.Kind() {
+	case reflect.Map, reflect.Func, reflect.Slice:
+		return va.Pointer() == vb.Pointer()
+	}
+	return false
+}
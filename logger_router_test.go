@@ -0,0 +1,63 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type testUncomparableLogger struct {
+	entryLevelMethods
+	entries []*ObjectLogEntry
+}
+
+func TestRouterLogger(t *testing.T) {
+	out, err, alert, fallback := NewBufferObjectLog(), NewBufferObjectLog(), NewBufferObjectLog(), NewBufferObjectLog()
+	router := NewRouterLogger(fallback).
+		AddLevelRoute(OBJECT_LOG_LEVEL_DEBUG, OBJECT_LOG_LEVEL_INFO, out).
+		AddLevelRoute(OBJECT_LOG_LEVEL_WARN, "", err).
+		AddRoute(LevelsFilter(OBJECT_LOG_LEVEL_ERROR, OBJECT_LOG_LEVEL_PANIC), alert, err)
+	ol := NewObjectLog(router)
+	ol.LogTrace("trace")
+	ol.LogDebug("debug")
+	ol.LogInfo("info")
+	ol.LogNotice("notice")
+	ol.LogWarn("warn")
+	ol.LogError("error")
+	router.Info("direct")
+
+	assert.Equal(t, "[DBG] debug\n[INF] info\n[INF] direct\n", out.Buffer().String())
+	assert.Equal(t, "[WRN] warn\n[ERR] error\n", err.Buffer().String())
+	assert.Equal(t, "[ERR] error\n", alert.Buffer().String())
+	assert.Equal(t, "[TRC] trace\n[NTC] notice\n", fallback.Buffer().String())
+}
+
+func TestRouterLogger_Args(t *testing.T) {
+	audit, db, rest := NewBufferObjectLog(), NewBufferObjectLog(), NewBufferObjectLog()
+	router := NewRouterLogger().
+		AddRoute(ArgFilter("audit", true), audit).
+		AddRoute(PrefixFilter("db*"), db).
+		SetDefault(rest)
+	NewObjectLog(router).SetLogArg("audit", true).LogInfo("login")
+	NewObjectLog(router).SetLogArg("audit", false).LogInfo("not audited")
+	NewObjectLog(router).SetLogPrefix("db: ").LogInfo("query")
+	NewObjectLog(router).SetLogPrefix("db: ").SetLogArg("audit", "true").LogInfo("both")
+
+	assert.Equal(t, strings.Join([]string{
+		`[INF] login :: {"audit":true}`,
+		`[INF] db: both :: {"audit":"true"}`,
+	}, "\n")+"\n", audit.Buffer().String())
+	assert.Equal(t, "[INF] db: query\n"+`[INF] db: both :: {"audit":"true"}`+"\n", db.Buffer().String())
+	assert.Equal(t, `[INF] not audited :: {"audit":false}`+"\n", rest.Buffer().String())
+}
+
+func TestRouterLogger_Route(t *testing.T) {
+	a, b := NewBufferObjectLog(), NewBufferObjectLog()
+	c := testUncomparableLogger{}
+	router := NewRouterLogger().
+		AddRoute(LevelFilter(OBJECT_LOG_LEVEL_INFO), a, c).
+		AddRoute(LevelFilter(OBJECT_LOG_LEVEL_WARN), a, b, c)
+	assert.Equal(t, []ObjectLogger{a, c}, router.Route(NewTextEntry(OBJECT_LOG_LEVEL_INFO, "")))
+	assert.Equal(t, []ObjectLogger{a, c, b, c}, router.Route(NewTextEntry(OBJECT_LOG_LEVEL_WARN, "")))
+	assert.Equal(t, []ObjectLogger{}, router.Route(NewTextEntry(OBJECT_LOG_LEVEL_DEBUG, "")))
+}
@@ -0,0 +1,495 @@
+package objectlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+SpoolLogger writes all entries to a queue on disk, from which they are delivered in order to an inner logger,
+like `*GelfLogger` or `*HTTPLogger`. If the inner logger fails, delivery is retried until it succeeds. The queue
+consists of segment files, which are removed once all their entries are delivered. If the queue exceeds the
+maximum size, then the oldest segments are dropped. The position of the last delivered entry is persisted, so
+that a restarted process continues delivery without duplicates.
+
+	gelf, _ := objectlog.NewGelfLogger("tcp", "graylog:12201", objectlog.GelfOptions{})
+	lg, err := objectlog.NewSpoolLogger(gelf, "/var/spool/myapp", objectlog.SpoolOptions{})
+	if err != nil {
+		panic(err)
+	}
+	defer lg.Close()
+	ol := objectlog.NewObjectLog(lg)
+
+Entries are delivered at least once: If the process dies after an entry is delivered but before the position is
+persisted, the entry is delivered again. Appended entries and the position are synced to disk before the write
+returns.
+*/
+type (
+	SpoolLogger struct {
+		entryLevelMethods
+		errorReporting
+		inner      EntryWriter
+		dir        string
+		options    SpoolOptions
+		mutex      sync.Mutex
+		segments   []*spoolSegment
+		writer     *os.File
+		reader     *os.File
+		readBuf    *bufio.Reader
+		readPos    int64
+		readOffset int64
+		dropped    uint64
+		notify     chan struct{}
+		stop       chan struct{}
+		done       chan struct{}
+		closeOnce  sync.Once
+	}
+
+	// SpoolOptions configure a *SpoolLogger
+	SpoolOptions struct {
+
+		// SegmentSize is the size in bytes, after which a new segment file is started. Defaults to 1 MiB.
+		SegmentSize int64
+
+		// MaxSize is the maximum size in bytes of all segments, before the oldest are dropped. Defaults
+		// to 64 MiB.
+		MaxSize int64
+
+		// RetryInterval is the time waited after the inner logger failed, defaults to 1 second
+		RetryInterval time.Duration
+
+		// Formatter is set on delivered entries, since formatters cannot be stored. Defaults to
+		// `DefaultFormatter`.
+		Formatter ObjectLogFormatter
+	}
+
+	spoolSegment struct {
+		id   uint64
+		size int64
+	}
+
+	// spoolRecord is the stored representation of an entry, one JSON object per line
+	spoolRecord struct {
+		Level   ObjectLogLevel         `json:"level"`
+		Time    time.Time              `json:"time"`
+		Prefix  string                 `json:"prefix,omitempty"`
+		Suffix  string                 `json:"suffix,omitempty"`
+		Message string                 `json:"msg"`
+		Args    map[string]interface{} `json:"args,omitempty"`
+	}
+)
+
+const (
+	spoolSegmentExt = ".spool"
+	spoolAckFile    = "ack"
+)
+
+// NewSpoolLogger creates new *SpoolLogger, which stores the queue in the directory and delivers to the inner
+// logger. Entries remaining from a previous process are delivered first.
+func NewSpoolLogger(inner EntryWriter, dir string, options SpoolOptions) (*SpoolLogger, error) {
+	if options.SegmentSize <= 0 {
+		options.SegmentSize = 1 << 20
+	}
+	if options.MaxSize <= 0 {
+		options.MaxSize = 64 << 20
+	}
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = time.Second
+	}
+	if options.Formatter == nil {
+		options.Formatter = DefaultFormatter
+	}
+	this := &SpoolLogger{
+		inner:   inner,
+		dir:     dir,
+		options: options,
+		notify:  make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	if err := this.open(); err != nil {
+		this.closeFiles()
+		return nil, err
+	}
+	go this.loop()
+	return this, nil
+}
+
+// SetErrorHandler sets the handler of write failures. If nil, then the global handler is used, see
+// `ErrorHandler`.
+func (this *SpoolLogger) SetErrorHandler(handler ErrorHandler) *SpoolLogger {
+	this.setErrorHandler(handler)
+	return this
+}
+
+// LogEntry appends the entry to the queue. If it cannot be stored, it is written to the inner logger
+// directly. FATAL entries are delivered before the process exits.
+func (this *SpoolLogger) LogEntry(entry *ObjectLogEntry) {
+	if err := this.WriteEntry(entry); err != nil {
+		this.report(this, err)
+		this.report(this, this.inner.WriteEntry(entry))
+	}
+	if entry.Level == OBJECT_LOG_LEVEL_FATAL {
+		this.Flush(5 * time.Second)
+		os.Exit(1)
+	}
+}
+
+// WriteEntry appends the entry to the queue
+func (this *SpoolLogger) WriteEntry(entry *ObjectLogEntry) error {
+	line, err := spoolEncode(entry)
+	if err != nil {
+		return err
+	}
+	this.mutex.Lock()
+	err = this.append(line)
+	this.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	select {
+	case this.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Flush waits until all entries are delivered, or the timeout is reached
+func (this *SpoolLogger) Flush(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		this.mutex.Lock()
+		empty := len(this.segments) == 1 && this.readOffset >= this.segments[0].size
+		this.mutex.Unlock()
+		if empty {
+			return nil
+		} else if time.Now().After(deadline) {
+			return fmt.Errorf("objectlog: spool not delivered within %s", timeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Dropped returns the amount of entries, which were dropped because the queue exceeded the maximum size
+func (this *SpoolLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&this.dropped)
+}
+
+// Close stops the delivery. Undelivered entries remain on disk for the next process.
+func (this *SpoolLogger) Close() error {
+	this.closeOnce.Do(func() {
+		close(this.stop)
+	})
+	<-this.done
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.closeFiles()
+}
+
+// open reads the existing segments and the acknowledged position and starts a new segment for writing
+func (this *SpoolLogger) open() error {
+	if err := os.MkdirAll(this.dir, 0755); err != nil {
+		return err
+	}
+	files, err := ioutil.ReadDir(this.dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if id, err := strconv.ParseUint(strings.TrimSuffix(file.Name(), spoolSegmentExt), 10, 64); err == nil && strings.HasSuffix(file.Name(), spoolSegmentExt) {
+			this.segments = append(this.segments, &spoolSegment{id, file.Size()})
+		}
+	}
+	sort.Slice(this.segments, func(i, j int) bool {
+		return this.segments[i].id < this.segments[j].id
+	})
+	ackID, ackOffset := this.readAck()
+	for len(this.segments) > 0 && this.segments[0].id < ackID {
+		os.Remove(this.segmentPath(this.segments[0].id))
+		this.segments = this.segments[1:]
+	}
+	if len(this.segments) > 0 && this.segments[0].id == ackID {
+		this.readOffset = ackOffset
+	}
+	if err := this.rotate(); err != nil {
+		return err
+	}
+	return this.openReader()
+}
+
+// append writes the line to the current segment, starts a new segment if it is full and drops the oldest
+// segments, if the maximum size is exceeded
+func (this *SpoolLogger) append(line []byte) error {
+	if this.writer == nil {
+		return fmt.Errorf("objectlog: spool is closed")
+	}
+	current := this.segments[len(this.segments)-1]
+	if current.size > 0 && current.size+int64(len(line)) > this.options.SegmentSize {
+		if err := this.rotate(); err != nil {
+			return err
+		}
+		current = this.segments[len(this.segments)-1]
+	}
+	n, err := this.writer.Write(line)
+	current.size += int64(n)
+	if err != nil {
+		return err
+	} else if err = this.writer.Sync(); err != nil {
+		return err
+	}
+	total := int64(0)
+	for _, segment := range this.segments {
+		total += segment.size
+	}
+	for total > this.options.MaxSize && len(this.segments) > 1 {
+		total -= this.segments[0].size
+		atomic.AddUint64(&this.dropped, this.countPending())
+		this.removeOldest()
+	}
+	return nil
+}
+
+// rotate starts a new segment for writing
+func (this *SpoolLogger) rotate() error {
+	id := uint64(1)
+	if len(this.segments) > 0 {
+		id = this.segments[len(this.segments)-1].id + 1
+	}
+	writer, err := os.OpenFile(this.segmentPath(id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if this.writer != nil {
+		this.writer.Close()
+	}
+	this.writer = writer
+	this.segments = append(this.segments, &spoolSegment{id: id})
+	return this.syncDir()
+}
+
+// openReader opens the oldest segment at the acknowledged offset
+func (this *SpoolLogger) openReader() error {
+	if this.reader != nil {
+		this.reader.Close()
+		this.reader = nil
+	}
+	reader, err := os.Open(this.segmentPath(this.segments[0].id))
+	if err != nil {
+		return err
+	}
+	if _, err := reader.Seek(this.readOffset, io.SeekStart); err != nil {
+		reader.Close()
+		return err
+	}
+	this.reader = reader
+	this.readBuf = bufio.NewReader(reader)
+	this.readPos = this.readOffset
+	return nil
+}
+
+// removeOldest removes the oldest segment and continues reading with the next
+func (this *SpoolLogger) removeOldest() {
+	if this.reader != nil {
+		this.reader.Close()
+		this.reader = nil
+	}
+	os.Remove(this.segmentPath(this.segments[0].id))
+	this.segments = this.segments[1:]
+	this.readOffset = 0
+	this.writeAck()
+	this.openReader()
+}
+
+// countPending returns the amount of undelivered entries in the oldest segment
+func (this *SpoolLogger) countPending() uint64 {
+	fh, err := os.Open(this.segmentPath(this.segments[0].id))
+	if err != nil {
+		return 0
+	}
+	defer fh.Close()
+	if _, err := fh.Seek(this.readOffset, io.SeekStart); err != nil {
+		return 0
+	}
+	count := uint64(0)
+	scanner := bufio.NewScanner(fh)
+	scanner.Buffer(nil, int(this.options.SegmentSize)+bufio.MaxScanTokenSize)
+	for scanner.Scan() {
+		count++
+	}
+	return count
+}
+
+// next reads the next undelivered line. Completely delivered segments, except the current segment for
+// writing, are removed.
+func (this *SpoolLogger) next() (line []byte, id uint64, offset int64, ok bool) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	for this.reader != nil {
+		line, err := this.readBuf.ReadBytes('\n')
+		this.readPos += int64(len(line))
+		if err == nil {
+			return line, this.segments[0].id, this.readPos, true
+		} else if len(this.segments) == 1 {
+			return nil, 0, 0, false
+		}
+		// incomplete lines at the end of older segments are left-overs of a crashed process
+		this.removeOldest()
+	}
+	return nil, 0, 0, false
+}
+
+// commit acknowledges the delivery of all entries up to the offset of the segment
+func (this *SpoolLogger) commit(id uint64, offset int64) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if len(this.segments) > 0 && this.segments[0].id == id {
+		this.readOffset = offset
+		this.report(this, this.writeAck())
+	}
+}
+
+// loop delivers the entries, until the logger is closed
+func (this *SpoolLogger) loop() {
+	defer close(this.done)
+	for {
+		line, id, offset, ok := this.next()
+		if !ok {
+			select {
+			case <-this.notify:
+				continue
+			case <-this.stop:
+				return
+			}
+		}
+		if entry, err := this.decode(line); err == nil {
+			for err := this.inner.WriteEntry(entry); err != nil; err = this.inner.WriteEntry(entry) {
+				this.report(this, err)
+				select {
+				case <-time.After(this.options.RetryInterval):
+				case <-this.stop:
+					return
+				}
+			}
+		}
+		this.commit(id, offset)
+	}
+}
+
+func (this *SpoolLogger) decode(line []byte) (*ObjectLogEntry, error) {
+	record := &spoolRecord{}
+	if err := json.Unmarshal(line, record); err != nil {
+		return nil, err
+	}
+	return &ObjectLogEntry{
+		Level:       record.Level,
+		Time:        record.Time,
+		Prefix:      record.Prefix,
+		Suffix:      record.Suffix,
+		Message:     "%s",
+		MessageArgs: []interface{}{record.Message},
+		Args:        record.Args,
+		Formatter:   this.options.Formatter,
+	}, nil
+}
+
+func (this *SpoolLogger) readAck() (uint64, int64) {
+	raw, err := ioutil.ReadFile(filepath.Join(this.dir, spoolAckFile))
+	if err != nil {
+		return 0, 0
+	}
+	var id uint64
+	var offset int64
+	if _, err := fmt.Sscanf(string(raw), "%d %d", &id, &offset); err != nil {
+		return 0, 0
+	}
+	return id, offset
+}
+
+// writeAck persists the acknowledged position by replacing the file, so that it is never partially written.
+// The file and the directory are synced, so that the position survives a crash.
+func (this *SpoolLogger) writeAck() error {
+	name := filepath.Join(this.dir, spoolAckFile)
+	fh, err := os.OpenFile(name+".tmp", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(fh, "%d %d\n", this.segments[0].id, this.readOffset)
+	if err == nil {
+		err = fh.Sync()
+	}
+	if closeErr := fh.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	} else if err = os.Rename(name+".tmp", name); err != nil {
+		return err
+	}
+	return this.syncDir()
+}
+
+// syncDir syncs the directory, so that created, renamed and removed files are persisted
+func (this *SpoolLogger) syncDir() error {
+	dir, err := os.Open(this.dir)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+func (this *SpoolLogger) segmentPath(id uint64) string {
+	return filepath.Join(this.dir, fmt.Sprintf("%020d%s", id, spoolSegmentExt))
+}
+
+func (this *SpoolLogger) closeFiles() error {
+	var err error
+	if this.writer != nil {
+		err = this.writer.Close()
+		this.writer = nil
+	}
+	if this.reader != nil {
+		this.reader.Close()
+		this.reader = nil
+	}
+	return err
+}
+
+// spoolEncode renders the entry as JSON line. Argument values, which cannot be encoded, are stored as text.
+func spoolEncode(entry *ObjectLogEntry) ([]byte, error) {
+	args := entry.LogArgs()
+	record := &spoolRecord{
+		Level:   entry.Level,
+		Time:    entry.Time,
+		Prefix:  entry.Prefix,
+		Suffix:  entry.Suffix,
+		Message: entry.Text(),
+		Args:    args,
+	}
+	raw, err := json.Marshal(record)
+	if err != nil {
+		record.Args = make(map[string]interface{}, len(args))
+		for key, value := range args {
+			if _, err := json.Marshal(value); err != nil {
+				value = fmt.Sprint(value)
+			}
+			record.Args[key] = value
+		}
+		if raw, err = json.Marshal(record); err != nil {
+			return nil, err
+		}
+	}
+	return append(raw, '\n'), nil
+}
@@ -0,0 +1,199 @@
+package objectlog
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testSpoolWriter records the text of written entries and fails while failing is set
+type testSpoolWriter struct {
+	mutex    sync.Mutex
+	failing  bool
+	attempts int
+	texts    []string
+	entries  []*ObjectLogEntry
+}
+
+func (this *testSpoolWriter) WriteEntry(entry *ObjectLogEntry) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.attempts++
+	if this.failing {
+		return fmt.Errorf("failing")
+	}
+	this.texts = append(this.texts, entry.Text())
+	this.entries = append(this.entries, entry)
+	return nil
+}
+
+func (this *testSpoolWriter) SetFailing(failing bool) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.failing = failing
+}
+
+func (this *testSpoolWriter) Texts() []string {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return append([]string{}, this.texts...)
+}
+
+func (this *testSpoolWriter) Attempts() int {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.attempts
+}
+
+func testSpoolDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "objectlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "spool")
+}
+
+func TestSpoolLogger(t *testing.T) {
+	dir := testSpoolDir(t)
+	defer os.RemoveAll(filepath.Dir(dir))
+	inner := &testSpoolWriter{}
+	lg, err := NewSpoolLogger(inner, dir, SpoolOptions{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer lg.Close()
+	ol := NewObjectLog(lg).SetLogPrefix("pre ").SetLogArg("foo", "bar")
+	ol.LogInfo("one")
+	ol.LogWarn("two %d", 2)
+	assert.Nil(t, lg.Flush(2*time.Second))
+	assert.Equal(t, []string{"one", "two 2"}, inner.Texts())
+
+	entry := inner.entries[1]
+	assert.Equal(t, OBJECT_LOG_LEVEL_WARN, entry.Level)
+	assert.Equal(t, "pre ", entry.Prefix)
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, entry.Args)
+	assert.Equal(t, `pre two 2 :: {"foo":"bar"}`, entry.String())
+	assert.False(t, entry.Time.IsZero())
+}
+
+func TestSpoolLogger_Retry(t *testing.T) {
+	dir := testSpoolDir(t)
+	defer os.RemoveAll(filepath.Dir(dir))
+	inner := &testSpoolWriter{failing: true}
+	lg, err := NewSpoolLogger(inner, dir, SpoolOptions{RetryInterval: 5 * time.Millisecond, SegmentSize: 100})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer lg.Close()
+	lg.SetErrorHandler(func(logger ObjectLogger, err error) {})
+	ol := NewObjectLog(lg)
+	for i := 0; i < 10; i++ {
+		ol.LogInfo("message %d", i)
+	}
+	assert.NotNil(t, lg.Flush(30*time.Millisecond))
+	assert.True(t, inner.Attempts() > 1)
+	assert.True(t, lg.Failures() > 0, "failed deliveries are reported")
+	assert.Empty(t, inner.Texts())
+
+	inner.SetFailing(false)
+	assert.Nil(t, lg.Flush(2*time.Second))
+	expect := []string{}
+	for i := 0; i < 10; i++ {
+		expect = append(expect, fmt.Sprintf("message %d", i))
+	}
+	assert.Equal(t, expect, inner.Texts())
+
+	segments, _ := filepath.Glob(filepath.Join(dir, "*.spool"))
+	assert.Len(t, segments, 1, "delivered segments are removed")
+}
+
+func TestSpoolLogger_Restart(t *testing.T) {
+	dir := testSpoolDir(t)
+	defer os.RemoveAll(filepath.Dir(dir))
+
+	inner := &testSpoolWriter{}
+	lg, err := NewSpoolLogger(inner, dir, SpoolOptions{RetryInterval: time.Millisecond})
+	if !assert.Nil(t, err) {
+		return
+	}
+	lg.SetErrorHandler(func(logger ObjectLogger, err error) {})
+	NewObjectLog(lg).LogInfo("delivered")
+	assert.Nil(t, lg.Flush(2*time.Second))
+	inner.SetFailing(true)
+	NewObjectLog(lg).LogInfo("pending 1")
+	NewObjectLog(lg).LogInfo("pending 2")
+	assert.Nil(t, lg.Close())
+	assert.Equal(t, []string{"delivered"}, inner.Texts())
+
+	inner = &testSpoolWriter{}
+	lg, err = NewSpoolLogger(inner, dir, SpoolOptions{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	NewObjectLog(lg).LogInfo("new")
+	assert.Nil(t, lg.Flush(2*time.Second))
+	assert.Nil(t, lg.Close())
+	assert.Equal(t, []string{"pending 1", "pending 2", "new"}, inner.Texts())
+
+	inner = &testSpoolWriter{}
+	lg, err = NewSpoolLogger(inner, dir, SpoolOptions{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, lg.Flush(2*time.Second))
+	assert.Nil(t, lg.Close())
+	assert.Empty(t, inner.Texts(), "acknowledged entries are not delivered again")
+}
+
+func TestSpoolLogger_MaxSize(t *testing.T) {
+	dir := testSpoolDir(t)
+	defer os.RemoveAll(filepath.Dir(dir))
+	inner := &testSpoolWriter{failing: true}
+	lg, err := NewSpoolLogger(inner, dir, SpoolOptions{
+		RetryInterval: time.Millisecond,
+		SegmentSize:   300,
+		MaxSize:       900,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer lg.Close()
+	ol := NewObjectLog(lg)
+	for i := 0; i < 50; i++ {
+		ol.LogInfo("message %02d", i)
+	}
+	assert.True(t, lg.Dropped() > 0)
+	inner.SetFailing(false)
+	assert.Nil(t, lg.Flush(2*time.Second))
+
+	texts := inner.Texts()
+	assert.True(t, len(texts) < 50)
+	assert.Equal(t, "message 49", texts[len(texts)-1])
+	for i := 1; i < len(texts); i++ {
+		assert.True(t, texts[i-1] < texts[i], "order %v", texts)
+	}
+	assert.True(t, uint64(len(texts))+lg.Dropped() >= 50)
+}
+
+func TestSpoolLogger_Closed(t *testing.T) {
+	dir := testSpoolDir(t)
+	defer os.RemoveAll(filepath.Dir(dir))
+	inner := &testSpoolWriter{}
+	lg, err := NewSpoolLogger(inner, dir, SpoolOptions{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	handler := &testErrorHandler{}
+	lg.SetErrorHandler(handler.Handle)
+	assert.Nil(t, lg.Close())
+	assert.Nil(t, lg.Close())
+	assert.NotNil(t, lg.WriteEntry(NewTextEntry(OBJECT_LOG_LEVEL_INFO, "closed")))
+	lg.Info("direct")
+	assert.Equal(t, []string{"direct"}, inner.Texts())
+	assert.Equal(t, []string{"objectlog: spool is closed"}, handler.errors)
+}
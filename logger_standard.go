@@ -10,6 +10,7 @@ StandardLogger is adapter the default "*log.Logger", included in the Go language
  */
 type (
 	StandardLogger struct {
+		errorReporting
 		logger *log.Logger
 	}
 )
@@ -35,21 +36,44 @@ func NewStandardLogger(logger ...*log.Logger) *StandardLogger {
 }
 
 func (this *StandardLogger) Debug(msg string) {
-	this.logger.Print("[DEBUG] " + msg)
+	this.output("[DEBUG] " + msg)
 }
 
 func (this *StandardLogger) Info(msg string) {
-	this.logger.Print("[INFO] " + msg)
+	this.output("[INFO] " + msg)
 }
 
 func (this *StandardLogger) Warn(msg string) {
-	this.logger.Print("[WARN] " + msg)
+	this.output("[WARN] " + msg)
 }
 
 func (this *StandardLogger) Error(msg string) {
-	this.logger.Print("[ERROR] " + msg)
+	this.output("[ERROR] " + msg)
 }
 
 func (this *StandardLogger) Fatal(msg string) {
-	this.logger.Fatal(msg)
+	this.output(msg)
+	os.Exit(1)
+}
+
+// Log writes the message of any other level prefixed by the level name in brackets, e.g. "[TRACE] "
+func (this *StandardLogger) Log(level ObjectLogLevel, msg string) {
+	this.output("[" + LevelName(level) + "] " + msg)
+}
+
+// WriteEntry writes the entry like `Log` and returns the error of writing
+func (this *StandardLogger) WriteEntry(entry *ObjectLogEntry) error {
+	return this.logger.Output(2, "["+LevelName(entry.Level)+"] "+entry.String())
+}
+
+// SetErrorHandler sets the handler of write failures. If nil, then the global handler is used, see
+// `ErrorHandler`.
+func (this *StandardLogger) SetErrorHandler(handler ErrorHandler) *StandardLogger {
+	this.setErrorHandler(handler)
+	return this
+}
+
+// output writes the message and reports failures
+func (this *StandardLogger) output(msg string) {
+	this.report(this, this.logger.Output(3, msg))
 }
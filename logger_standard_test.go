@@ -16,10 +16,12 @@ func TestStandardObjectLog(t *testing.T) {
 	lg.Info("From Info")
 	lg.Warn("From Warn")
 	lg.Error("From Error")
+	lg.Log(OBJECT_LOG_LEVEL_TRACE, "From Trace")
 	assert.Equal(t, strings.Join([]string{
 		"[DEBUG] From Debug",
 		"[INFO] From Info",
 		"[WARN] From Warn",
 		"[ERROR] From Error",
+		"[TRACE] From Trace",
 	}, "\n")+"\n", buf.String())
 }
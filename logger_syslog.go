@@ -0,0 +1,96 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package objectlog
+
+import (
+	"log/syslog"
+	"os"
+)
+
+/*
+SyslogLogger is adapter for the "*syslog.Writer", included in the Go language standard libraries. Levels are
+mapped to the syslog severities, TRACE to debug and PANIC as well as FATAL to critical. Other levels are
+mapped by their severity, see `SyslogSeverity`.
+
+	writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, "myapp")
+	lg := objectlog.NewSyslogLogger(writer)
+*/
+type (
+	SyslogLogger struct {
+		errorReporting
+		writer *syslog.Writer
+	}
+)
+
+// NewSyslogLogger creates new *SyslogLogger writing to the provided *syslog.Writer
+func NewSyslogLogger(writer *syslog.Writer) *SyslogLogger {
+	return &SyslogLogger{
+		writer: writer,
+	}
+}
+
+// SetErrorHandler sets the handler of write failures. If nil, then the global handler is used, see
+// `ErrorHandler`.
+func (this *SyslogLogger) SetErrorHandler(handler ErrorHandler) *SyslogLogger {
+	this.setErrorHandler(handler)
+	return this
+}
+
+// Close closes the connection to the syslog server
+func (this *SyslogLogger) Close() error {
+	return this.writer.Close()
+}
+
+// WriteEntry writes the formatted entry with the syslog severity of its level and returns the error of
+// writing
+func (this *SyslogLogger) WriteEntry(entry *ObjectLogEntry) error {
+	msg := entry.String()
+	switch SyslogSeverity(entry.Level) {
+	case 7:
+		return this.writer.Debug(msg)
+	case 6:
+		return this.writer.Info(msg)
+	case 5:
+		return this.writer.Notice(msg)
+	case 4:
+		return this.writer.Warning(msg)
+	case 3:
+		return this.writer.Err(msg)
+	}
+	return this.writer.Crit(msg)
+}
+
+func (this *SyslogLogger) Trace(msg string) {
+	this.report(this, this.writer.Debug(msg))
+}
+
+func (this *SyslogLogger) Debug(msg string) {
+	this.report(this, this.writer.Debug(msg))
+}
+
+func (this *SyslogLogger) Info(msg string) {
+	this.report(this, this.writer.Info(msg))
+}
+
+func (this *SyslogLogger) Notice(msg string) {
+	this.report(this, this.writer.Notice(msg))
+}
+
+func (this *SyslogLogger) Warn(msg string) {
+	this.report(this, this.writer.Warning(msg))
+}
+
+func (this *SyslogLogger) Error(msg string) {
+	this.report(this, this.writer.Err(msg))
+}
+
+func (this *SyslogLogger) Panic(msg string) {
+	this.report(this, this.writer.Crit(msg))
+}
+
+// Fatal writes the message with critical severity and exits
+func (this *SyslogLogger) Fatal(msg string) {
+	this.report(this, this.writer.Crit(msg))
+	os.Exit(1)
+}
@@ -0,0 +1,55 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"log/syslog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogLogger(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer conn.Close()
+	writer, err := syslog.Dial("udp", conn.LocalAddr().String(), syslog.LOG_LOCAL0, "test")
+	if !assert.Nil(t, err) {
+		return
+	}
+	lg := NewSyslogLogger(writer)
+	lg.Trace("From Trace")
+	lg.Notice("From Notice")
+	lg.Warn("From Warn")
+	lg.Error("From Error")
+	entry := NewTextEntry(OBJECT_LOG_LEVEL_NOTICE, "From WriteEntry")
+	entry.Args = map[string]interface{}{"foo": "bar"}
+	entry.Formatter = DefaultFormatter
+	assert.Nil(t, lg.WriteEntry(entry))
+	assert.Nil(t, lg.WriteEntry(NewTextEntry(OBJECT_LOG_LEVEL_PANIC, "From WriteEntry")))
+
+	received := []string{}
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	for i := 0; i < 6; i++ {
+		n, _, err := conn.ReadFrom(buf)
+		if !assert.Nil(t, err) {
+			return
+		}
+		msg := string(buf[:n])
+		received = append(received, msg[:strings.Index(msg, ">")+1]+msg[strings.Index(msg, "]: ")+3:])
+	}
+	assert.Equal(t, []string{
+		"<135>From Trace\n",
+		"<133>From Notice\n",
+		"<132>From Warn\n",
+		"<131>From Error\n",
+		"<133>From WriteEntry :: {\"foo\":\"bar\"}\n",
+		"<130>From WriteEntry\n",
+	}, received)
+}
@@ -0,0 +1,63 @@
+package objectlog
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+/*
+WriterLogger writes each message as single line to an `io.Writer`, without adding anything. It is meant for
+formatters which produce complete lines, like `JSONFormatter`, `LogfmtFormatter` or `NewTextFormatter`.
+
+	lg := objectlog.NewWriterLogger(os.Stdout, objectlog.JSONFormatter)
+*/
+type (
+	WriterLogger struct {
+		entryLevelMethods
+		errorReporting
+		mutex     sync.Mutex
+		writer    io.Writer
+		formatter ObjectLogFormatter
+	}
+)
+
+// NewWriterLogger creates new *WriterLogger writing to the writer. If the formatter is nil, then the
+// formatter of the `ObjectLog` is used.
+func NewWriterLogger(writer io.Writer, formatter ObjectLogFormatter) *WriterLogger {
+	this := &WriterLogger{
+		writer:    writer,
+		formatter: formatter,
+	}
+	this.entryLevelMethods = entryLevelMethods{this.LogEntry}
+	return this
+}
+
+// Writer returns the writer, the messages are written to
+func (this *WriterLogger) Writer() io.Writer {
+	return this.writer
+}
+
+// SetErrorHandler sets the handler of write failures. If nil, then the global handler is used, see
+// `ErrorHandler`.
+func (this *WriterLogger) SetErrorHandler(handler ErrorHandler) *WriterLogger {
+	this.setErrorHandler(handler)
+	return this
+}
+
+// LogEntry writes the formatted entry ended with a new line. FATAL entries exit after writing.
+func (this *WriterLogger) LogEntry(entry *ObjectLogEntry) {
+	this.report(this, this.WriteEntry(entry))
+	if entry.Level == OBJECT_LOG_LEVEL_FATAL {
+		os.Exit(1)
+	}
+}
+
+// WriteEntry writes the formatted entry ended with a new line
+func (this *WriterLogger) WriteEntry(entry *ObjectLogEntry) error {
+	line := entry.Format(this.formatter) + "\n"
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	_, err := io.WriteString(this.writer, line)
+	return err
+}
@@ -0,0 +1,45 @@
+package objectlog
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestWriterLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lg := NewWriterLogger(buf, nil)
+	assert.Equal(t, buf, lg.Writer())
+	ol := NewObjectLog(lg).SetLogPrefix("pre ").SetLogArg("foo", "bar")
+	ol.LogInfo("hello %s", "you")
+	lg.Warn("direct")
+	assert.Equal(t, strings.Join([]string{
+		`pre hello you :: {"foo":"bar"}`,
+		`direct`,
+	}, "\n")+"\n", buf.String())
+}
+
+func TestWriterLogger_Formatter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ol := NewObjectLog(NewWriterLogger(buf, LogfmtFormatter)).SetLogArg("foo", "bar")
+	ol.LogError("hello")
+	assert.Regexp(t, `^time=\S+ level=error msg=hello foo=bar\n$`, buf.String())
+}
+
+type testFailingWriter struct{}
+
+func (this testFailingWriter) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("disk full")
+}
+
+func TestWriterLogger_WriteEntry(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, NewWriterLogger(buf, nil).WriteEntry(NewTextEntry(OBJECT_LOG_LEVEL_INFO, "hello")))
+	assert.Equal(t, "hello\n", buf.String())
+	err := NewWriterLogger(testFailingWriter{}, nil).WriteEntry(NewTextEntry(OBJECT_LOG_LEVEL_INFO, "hello"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "disk full", err.Error())
+	}
+}
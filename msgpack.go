@@ -0,0 +1,342 @@
+package objectlog
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// msgpackExt is an extension value of msgpack, like the EventTime of the Fluentd forward protocol
+type msgpackExt struct {
+	Type int8
+	Data []byte
+}
+
+// msgpackEncode appends the msgpack encoding of the value. Types without a msgpack representation are
+// encoded as they would be in JSON.
+func msgpackEncode(buf []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, 0xc0)
+	case bool:
+		if v {
+			return append(buf, 0xc3)
+		}
+		return append(buf, 0xc2)
+	case int:
+		return msgpackInt(buf, int64(v))
+	case int8:
+		return msgpackInt(buf, int64(v))
+	case int16:
+		return msgpackInt(buf, int64(v))
+	case int32:
+		return msgpackInt(buf, int64(v))
+	case int64:
+		return msgpackInt(buf, v)
+	case uint:
+		return msgpackUint(buf, uint64(v))
+	case uint8:
+		return msgpackUint(buf, uint64(v))
+	case uint16:
+		return msgpackUint(buf, uint64(v))
+	case uint32:
+		return msgpackUint(buf, uint64(v))
+	case uint64:
+		return msgpackUint(buf, v)
+	case float32:
+		buf = append(buf, 0xca)
+		return msgpackAppend32(buf, math.Float32bits(v))
+	case float64:
+		buf = append(buf, 0xcb)
+		return msgpackAppend64(buf, math.Float64bits(v))
+	case string:
+		return msgpackString(buf, v)
+	case []byte:
+		return msgpackBin(buf, v)
+	case time.Time:
+		return msgpackString(buf, v.Format(time.RFC3339Nano))
+	case error:
+		return msgpackString(buf, v.Error())
+	case fmt.Stringer:
+		return msgpackString(buf, v.String())
+	case msgpackExt:
+		return msgpackExtension(buf, v)
+	case []interface{}:
+		buf = msgpackHeader(buf, len(v), 0x90, 16, 0xdc)
+		for _, item := range v {
+			buf = msgpackEncode(buf, item)
+		}
+		return buf
+	case map[string]interface{}:
+		buf = msgpackHeader(buf, len(v), 0x80, 16, 0xde)
+		for key, item := range v {
+			buf = msgpackString(buf, key)
+			buf = msgpackEncode(buf, item)
+		}
+		return buf
+	}
+	var generic interface{}
+	if raw, err := json.Marshal(value); err != nil {
+		return msgpackString(buf, fmt.Sprint(value))
+	} else if err := json.Unmarshal(raw, &generic); err != nil {
+		return msgpackString(buf, string(raw))
+	}
+	return msgpackEncode(buf, generic)
+}
+
+func msgpackInt(buf []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return msgpackUint(buf, uint64(v))
+	case v >= -32:
+		return append(buf, byte(v))
+	case v >= math.MinInt8:
+		return append(buf, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return msgpackAppend16(append(buf, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return msgpackAppend32(append(buf, 0xd2), uint32(v))
+	}
+	return msgpackAppend64(append(buf, 0xd3), uint64(v))
+}
+
+func msgpackUint(buf []byte, v uint64) []byte {
+	switch {
+	case v <= 0x7f:
+		return append(buf, byte(v))
+	case v <= math.MaxUint8:
+		return append(buf, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return msgpackAppend16(append(buf, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return msgpackAppend32(append(buf, 0xce), uint32(v))
+	}
+	return msgpackAppend64(append(buf, 0xcf), v)
+}
+
+func msgpackString(buf []byte, v string) []byte {
+	switch n := len(v); {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = msgpackAppend16(append(buf, 0xda), uint16(n))
+	default:
+		buf = msgpackAppend32(append(buf, 0xdb), uint32(n))
+	}
+	return append(buf, v...)
+}
+
+func msgpackBin(buf []byte, v []byte) []byte {
+	switch n := len(v); {
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		buf = msgpackAppend16(append(buf, 0xc5), uint16(n))
+	default:
+		buf = msgpackAppend32(append(buf, 0xc6), uint32(n))
+	}
+	return append(buf, v...)
+}
+
+func msgpackExtension(buf []byte, v msgpackExt) []byte {
+	switch n := len(v.Data); {
+	case n == 1:
+		buf = append(buf, 0xd4)
+	case n == 2:
+		buf = append(buf, 0xd5)
+	case n == 4:
+		buf = append(buf, 0xd6)
+	case n == 8:
+		buf = append(buf, 0xd7)
+	case n == 16:
+		buf = append(buf, 0xd8)
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xc7, byte(n))
+	case n <= math.MaxUint16:
+		buf = msgpackAppend16(append(buf, 0xc8), uint16(n))
+	default:
+		buf = msgpackAppend32(append(buf, 0xc9), uint32(n))
+	}
+	return append(append(buf, byte(v.Type)), v.Data...)
+}
+
+// msgpackHeader appends the header of an array or map with the size
+func msgpackHeader(buf []byte, n int, fix byte, fixMax int, code byte) []byte {
+	switch {
+	case n < fixMax:
+		return append(buf, fix|byte(n))
+	case n <= math.MaxUint16:
+		return msgpackAppend16(append(buf, code), uint16(n))
+	}
+	return msgpackAppend32(append(buf, code+1), uint32(n))
+}
+
+// msgpackDecode reads a single value. Maps are decoded as map[string]interface{}, if all keys are strings,
+// integers as int64 or uint64, extensions as msgpackExt.
+func msgpackDecode(reader *bufio.Reader) (interface{}, error) {
+	code, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case code <= 0x7f:
+		return int64(code), nil
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+	case code&0xf0 == 0x80:
+		return msgpackDecodeMap(reader, int(code&0x0f))
+	case code&0xf0 == 0x90:
+		return msgpackDecodeArray(reader, int(code&0x0f))
+	case code&0xe0 == 0xa0:
+		raw, err := msgpackRead(reader, int(code&0x1f))
+		return string(raw), err
+	}
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := msgpackReadSize(reader, code-0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return msgpackRead(reader, n)
+	case 0xc7, 0xc8, 0xc9:
+		n, err := msgpackReadSize(reader, code-0xc7)
+		if err != nil {
+			return nil, err
+		}
+		return msgpackDecodeExt(reader, n)
+	case 0xca:
+		raw, err := msgpackRead(reader, 4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(raw)), nil
+	case 0xcb:
+		raw, err := msgpackRead(reader, 8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(raw)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		raw, err := msgpackRead(reader, 1<<(code-0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return msgpackBytesUint(raw), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		raw, err := msgpackRead(reader, 1<<(code-0xd0))
+		if err != nil {
+			return nil, err
+		}
+		shift := uint(64 - 8*len(raw))
+		return int64(msgpackBytesUint(raw)<<shift) >> shift, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return msgpackDecodeExt(reader, 1<<(code-0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := msgpackReadSize(reader, code-0xd9)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := msgpackRead(reader, n)
+		return string(raw), err
+	case 0xdc, 0xdd:
+		n, err := msgpackReadSize(reader, code-0xdc+1)
+		if err != nil {
+			return nil, err
+		}
+		return msgpackDecodeArray(reader, n)
+	case 0xde, 0xdf:
+		n, err := msgpackReadSize(reader, code-0xde+1)
+		if err != nil {
+			return nil, err
+		}
+		return msgpackDecodeMap(reader, n)
+	}
+	return nil, fmt.Errorf("objectlog: unsupported msgpack code 0x%x", code)
+}
+
+func msgpackDecodeArray(reader *bufio.Reader, n int) (interface{}, error) {
+	list := make([]interface{}, n)
+	for i := range list {
+		value, err := msgpackDecode(reader)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = value
+	}
+	return list, nil
+}
+
+func msgpackDecodeMap(reader *bufio.Reader, n int) (interface{}, error) {
+	data := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key, err := msgpackDecode(reader)
+		if err != nil {
+			return nil, err
+		}
+		value, err := msgpackDecode(reader)
+		if err != nil {
+			return nil, err
+		}
+		data[fmt.Sprint(key)] = value
+	}
+	return data, nil
+}
+
+func msgpackDecodeExt(reader *bufio.Reader, n int) (interface{}, error) {
+	typ, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	data, err := msgpackRead(reader, n)
+	if err != nil {
+		return nil, err
+	}
+	return msgpackExt{int8(typ), data}, nil
+}
+
+// msgpackReadSize reads a size of 1, 2 or 4 bytes, given by the power of two
+func msgpackReadSize(reader *bufio.Reader, power byte) (int, error) {
+	raw, err := msgpackRead(reader, 1<<power)
+	if err != nil {
+		return 0, err
+	}
+	return int(msgpackBytesUint(raw)), nil
+}
+
+func msgpackRead(reader *bufio.Reader, n int) ([]byte, error) {
+	raw := make([]byte, n)
+	_, err := io.ReadFull(reader, raw)
+	return raw, err
+}
+
+func msgpackAppend16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v>>8), byte(v))
+}
+
+func msgpackAppend32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func msgpackAppend64(buf []byte, v uint64) []byte {
+	return msgpackAppend32(msgpackAppend32(buf, uint32(v>>32)), uint32(v))
+}
+
+func msgpackBytesUint(raw []byte) uint64 {
+	v := uint64(0)
+	for _, b := range raw {
+		v = v<<8 | uint64(b)
+	}
+	return v
+}
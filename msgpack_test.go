@@ -0,0 +1,52 @@
+package objectlog
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testMsgpackRoundtrip(t *testing.T, value interface{}) interface{} {
+	decoded, err := msgpackDecode(bufio.NewReader(bytes.NewReader(msgpackEncode(nil, value))))
+	assert.Nil(t, err, fmt.Sprintf("%#v", value))
+	return decoded
+}
+
+func TestMsgpack(t *testing.T) {
+	for _, v := range []int64{0, 1, 127, 128, 255, 256, 65535, 65536, math.MaxUint32, math.MaxUint32 + 1, -1, -32, -33, -128, -129, -32768, -32769, math.MinInt32, math.MinInt32 - 1, math.MinInt64} {
+		assert.EqualValues(t, v, testMsgpackRoundtrip(t, v), "%d", v)
+	}
+	assert.Equal(t, uint64(math.MaxUint64), testMsgpackRoundtrip(t, uint64(math.MaxUint64)))
+	assert.Equal(t, int64(3), testMsgpackRoundtrip(t, uint8(3)))
+	assert.Equal(t, float32(1.5), testMsgpackRoundtrip(t, float32(1.5)))
+	assert.Equal(t, 2.25, testMsgpackRoundtrip(t, 2.25))
+	assert.Equal(t, nil, testMsgpackRoundtrip(t, nil))
+	assert.Equal(t, true, testMsgpackRoundtrip(t, true))
+	assert.Equal(t, false, testMsgpackRoundtrip(t, false))
+	for _, n := range []int{0, 31, 32, 255, 256, 65536} {
+		assert.Equal(t, strings.Repeat("x", n), testMsgpackRoundtrip(t, strings.Repeat("x", n)))
+	}
+	assert.Equal(t, []byte("bin"), testMsgpackRoundtrip(t, []byte("bin")))
+	assert.Equal(t, msgpackExt{0, []byte{1, 2, 3, 4, 5, 6, 7, 8}}, testMsgpackRoundtrip(t, msgpackExt{0, []byte{1, 2, 3, 4, 5, 6, 7, 8}}))
+	assert.Equal(t, msgpackExt{5, []byte{1, 2, 3}}, testMsgpackRoundtrip(t, msgpackExt{5, []byte{1, 2, 3}}))
+	assert.Equal(t, "failed", testMsgpackRoundtrip(t, fmt.Errorf("failed")))
+	ts := time.Date(2039, 12, 24, 23, 59, 59, 0, time.UTC)
+	assert.Equal(t, "2039-12-24T23:59:59Z", testMsgpackRoundtrip(t, ts))
+
+	list := make([]interface{}, 20)
+	for i := range list {
+		list[i] = int64(i)
+	}
+	assert.Equal(t, list, testMsgpackRoundtrip(t, list))
+	assert.Equal(t, map[string]interface{}{"a": int64(1), "b": []interface{}{"c"}}, testMsgpackRoundtrip(t, map[string]interface{}{"a": 1, "b": []interface{}{"c"}}))
+	assert.Equal(t, map[string]interface{}{"Name": "foo", "n": []interface{}{1.0, 2.0}}, testMsgpackRoundtrip(t, struct {
+		Name string
+		N    []int `json:"n"`
+	}{"foo", []int{1, 2}}))
+	assert.IsType(t, "", testMsgpackRoundtrip(t, func() {}))
+}
@@ -33,6 +33,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 type (
@@ -56,6 +58,9 @@ type (
 	// ObjectLogFormatter is function signature to format log message for log output
 	ObjectLogFormatter func(level ObjectLogLevel, prefix, suffix, msg string, msgArgs []interface{}, logArgs map[string]interface{}) string
 
+	// ObjectLogAffixFunc is function signature to compute a prefix or suffix whenever a log message is written
+	ObjectLogAffixFunc func(level ObjectLogLevel, logArgs map[string]interface{}) string
+
 	// ObjectLogLevel represents log level
 	ObjectLogLevel string
 
@@ -66,6 +71,14 @@ type (
 		suffix    string
 		formatter ObjectLogFormatter
 		args      map[string]interface{}
+		repanic   bool
+		level     ObjectLogLevel
+		name      string
+		separator string
+		parent    *ObjectLog
+		prefixFn  ObjectLogAffixFunc
+		suffixFn  ObjectLogAffixFunc
+		override  *atomic.Value
 	}
 )
 
@@ -97,21 +110,27 @@ var (
 		}, "")
 	}
 
-	// DefaultLogger is an instance of `*StandardObjectLogger` and can be globally overwritten.
-	// It is used in `NewObjectLog`, if no logger is provided.
+	// DefaultLogger is an instance of `*StandardObjectLogger` and can be globally overwritten, preferably
+	// with `SetDefaultLogger`. It is used by all `ObjectLog` created without a logger in `NewObjectLog`.
 	DefaultLogger ObjectLogger = NewStandardLogger()
+
+	// DefaultChildSeparator is appended to the name of a child, when composing it's prefix in `LogChild`.
+	// It is used in `NewObjectLog` and can be changed per instance with `SetLogChildSeparator`.
+	DefaultChildSeparator = ": "
 )
 
 // NewObjectLog creates new ObjectLog instance using default formatter and provided logger. If no logger
-// is provided, then `DefaultLogger` is used
+// is provided, then `DefaultLogger` is used, as it is set at the time a message is written
 func NewObjectLog(logger ...ObjectLogger) *ObjectLog {
 	if len(logger) == 0 {
-		logger = []ObjectLogger{DefaultLogger}
+		logger = []ObjectLogger{defaultProxy}
 	}
 	return &ObjectLog{
 		logger:    logger[0],
 		formatter: DefaultFormatter,
 		args:      map[string]interface{}{},
+		separator: DefaultChildSeparator,
+		override:  &atomic.Value{},
 	}
 }
 
@@ -126,6 +145,13 @@ func (this *ObjectLog) LogCloneObjectLog() *ObjectLog {
 	clone.formatter = this.formatter
 	clone.prefix = this.prefix
 	clone.suffix = this.suffix
+	clone.prefixFn = this.prefixFn
+	clone.suffixFn = this.suffixFn
+	clone.repanic = this.repanic
+	clone.level = this.level
+	clone.name = this.name
+	clone.separator = this.separator
+	clone.parent = this.parent
 	for k, v := range this.args {
 		clone.args[k] = v
 	}
@@ -142,6 +168,7 @@ func (this *ObjectLog) LogCloneObjectLog() *ObjectLog {
 //	obj.SetPrefix(obj.ID() + ": ")
 func (this *ObjectLog) SetLogPrefix(prefix string) *ObjectLog {
 	this.prefix = prefix
+	this.override = &atomic.Value{}
 	return this
 }
 
@@ -162,6 +189,36 @@ func (this *ObjectLog) LogSuffix() string {
 	return this.suffix
 }
 
+// SetLogPrefixFunc sets a function which computes the prefix whenever a message is written. It takes
+// precedence over the prefix set with `SetLogPrefix`. Use nil to remove the function.
+//	obj.SetLogPrefixFunc(func(level objectlog.ObjectLogLevel, args map[string]interface{}) string {
+//		return obj.Peer() + ": "
+//	})
+func (this *ObjectLog) SetLogPrefixFunc(fn ObjectLogAffixFunc) *ObjectLog {
+	this.prefixFn = fn
+	return this
+}
+
+// LogPrefixFunc returns the current prefix function (can be nil)
+func (this *ObjectLog) LogPrefixFunc() ObjectLogAffixFunc {
+	return this.prefixFn
+}
+
+// SetLogSuffixFunc sets a function which computes the suffix whenever a message is written. It takes
+// precedence over the suffix set with `SetLogSuffix`. Use nil to remove the function.
+//	obj.SetLogSuffixFunc(func(level objectlog.ObjectLogLevel, args map[string]interface{}) string {
+//		return fmt.Sprintf(" (state: %s)", obj.State())
+//	})
+func (this *ObjectLog) SetLogSuffixFunc(fn ObjectLogAffixFunc) *ObjectLog {
+	this.suffixFn = fn
+	return this
+}
+
+// LogSuffixFunc returns the current suffix function (can be nil)
+func (this *ObjectLog) LogSuffixFunc() ObjectLogAffixFunc {
+	return this.suffixFn
+}
+
 /*
 ------------------------------------
   ARGS
@@ -169,75 +226,203 @@ func (this *ObjectLog) LogSuffix() string {
 */
 
 // SetLogArgs defines all args which should be logged on every log message. Overwrites existing args!
+// The args are copied, later changes of the provided map are not logged.
 //	obj.SetArgs(map[string]interface{}{"name": obj.Name()})
 func (this *ObjectLog) SetLogArgs(args map[string]interface{}) *ObjectLog {
-	if args == nil {
-		args = map[string]interface{}{}
-	}
-	this.args = args
+	this.args = copyLogArgs(args, 0)
 	return this
 }
 
-// SetLogArg sets a single log argument. Overwrites existing arg with the same name.
+// SetLogArg sets a single log argument. Overwrites existing arg with the same name. Values which implement
+// `LogValuer` or are functions returning a value are evaluated whenever a message is written.
 //	obj.SetArg("name", obj.Name())
+//	obj.SetArg("queue", func() interface{} { return len(obj.queue) })
 func (this *ObjectLog) SetLogArg(key string, value interface{}) *ObjectLog {
-	this.args[key] = value
+	args := copyLogArgs(this.args, 1)
+	args[key] = value
+	this.args = args
 	return this
 }
 
-// LogArgs returns all currently set log arguments.
+// LogArgs returns all currently set log arguments. The map is shared with the written messages and must
+// not be modified, use `SetLogArg` instead.
 func (this *ObjectLog) LogArgs() map[string]interface{} {
 	return this.args
 }
 
+// copyLogArgs returns a copy of the args with room for more. Args are never modified in place, as written
+// messages keep them.
+func copyLogArgs(args map[string]interface{}, more int) map[string]interface{} {
+	copied := make(map[string]interface{}, len(args)+more)
+	for k, v := range args {
+		copied[k] = v
+	}
+	return copied
+}
+
 /*
 ------------------------------------
   LOGGER
 ------------------------------------
 */
 
-// SetLogger replaces the current logger with another
+// SetLogger replaces the current logger with another. Setting a nil logger on a child makes it use the
+// logger of it's parent again.
 func (this *ObjectLog) SetLogger(logger ObjectLogger) *ObjectLog {
 	this.logger = logger
 	return this
 }
 
-// Logger returns the currently configured logger
+// Logger returns the currently configured logger. Children without an own logger return the logger
+// of their parent.
 func (this *ObjectLog) Logger() ObjectLogger {
+	if this.logger == nil && this.parent != nil {
+		return this.parent.Logger()
+	}
 	return this.logger
 }
 
+/*
+------------------------------------
+  FORMATTER
+------------------------------------
+*/
+
+// SetLogFormatter replaces the current formatter with another
+//	obj.SetLogFormatter(objectlog.DefaultFormatter)
+func (this *ObjectLog) SetLogFormatter(formatter ObjectLogFormatter) *ObjectLog {
+	this.formatter = formatter
+	return this
+}
+
+// LogFormatter returns the currently configured formatter
+func (this *ObjectLog) LogFormatter() ObjectLogFormatter {
+	return this.formatter
+}
+
+/*
+------------------------------------
+  LEVEL
+------------------------------------
+*/
+
+// SetLogLevel sets the minimum level of log messages which are written. Messages with a lower level are
+// discarded. An empty level writes all messages, or, for children, uses the level of the parent.
+//	obj.SetLogLevel(objectlog.OBJECT_LOG_LEVEL_WARN)
+func (this *ObjectLog) SetLogLevel(level ObjectLogLevel) *ObjectLog {
+	this.level = level
+	return this
+}
+
+// LogLevel returns the current minimum log level (can be empty string)
+func (this *ObjectLog) LogLevel() ObjectLogLevel {
+	if this.level == "" && this.parent != nil {
+		return this.parent.LogLevel()
+	}
+	return this.level
+}
+
+// LogEnabled returns whether messages in the given level would be written. Object levels set at runtime
+// take precedence over the level of the object, which in turn takes precedence over the global level.
+// Messages enabled by the verbose level are always written. See `SetObjectLevel`, `SetGlobalLevel` and
+// `SetVerboseLevel`.
+func (this *ObjectLog) LogEnabled(level ObjectLogLevel) bool {
+	if verbose := VerboseLevel(); verbose != "" && level.Enabled(verbose) {
+		return true
+	}
+	if min, ok := objectLevel(this); ok {
+		return level.Enabled(min)
+	}
+	if min := this.LogLevel(); min != "" {
+		return level.Enabled(min)
+	}
+	return level.Enabled(GlobalLevel())
+}
+
 /*
 ------------------------------------
   LOG METHODS
 ------------------------------------
 */
 
-func (this *ObjectLog) build(level ObjectLogLevel, msg string, args ...interface{}) string {
-	return this.formatter(level, this.prefix, this.suffix, msg, args, this.args)
+func (this *ObjectLog) build(level ObjectLogLevel, fields map[string]interface{}, msg string, args ...interface{}) *ObjectLogEntry {
+	// the args of the object are not copied, but replaced when they change, so the entry can keep them
+	entry := &ObjectLogEntry{
+		Level:       level,
+		Time:        time.Now(),
+		Prefix:      this.prefix,
+		Suffix:      this.suffix,
+		Message:     msg,
+		MessageArgs: args,
+		Args:        resolveLogArgs(this.args),
+		Fields:      resolveLogArgs(fields),
+		Formatter:   this.formatter,
+		callers:     entryCallers(2),
+	}
+	if this.prefixFn != nil || this.suffixFn != nil {
+		logArgs := entry.LogArgs()
+		if this.prefixFn != nil {
+			entry.Prefix = this.prefixFn(level, logArgs)
+		}
+		if this.suffixFn != nil {
+			entry.Suffix = this.suffixFn(level, logArgs)
+		}
+	}
+	return entry
+}
+
+func (this *ObjectLog) log(level ObjectLogLevel, fields map[string]interface{}, msg string, args []interface{}) {
+	if level == OBJECT_LOG_LEVEL_FATAL || this.LogEnabled(level) {
+		writeEntry(this.Logger(), this.build(level, fields, msg, args...))
+	}
+	if level == OBJECT_LOG_LEVEL_PANIC {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
+
+// Log writes the log message in the given level, which can be any registered level. See `RegisterLevel`.
+//	obj.Log(objectlog.OBJECT_LOG_LEVEL_NOTICE, "Hello %s", "you")
+func (this *ObjectLog) Log(level ObjectLogLevel, msg string, args ...interface{}) {
+	this.log(level, nil, msg, args)
+}
+
+// LogTrace writes the log message in TRACE level
+func (this *ObjectLog) LogTrace(msg string, args ...interface{}) {
+	this.log(OBJECT_LOG_LEVEL_TRACE, nil, msg, args)
 }
 
 // LogDebug writes the log message in DEBUG level
 func (this *ObjectLog) LogDebug(msg string, args ...interface{}) {
-	this.logger.Debug(this.build(OBJECT_LOG_LEVEL_DEBUG, msg, args...))
+	this.log(OBJECT_LOG_LEVEL_DEBUG, nil, msg, args)
 }
 
 // LogInfo writes the log message in INFO level
 func (this *ObjectLog) LogInfo(msg string, args ...interface{}) {
-	this.logger.Info(this.build(OBJECT_LOG_LEVEL_INFO, msg, args...))
+	this.log(OBJECT_LOG_LEVEL_INFO, nil, msg, args)
+}
+
+// LogNotice writes the log message in NOTICE level
+func (this *ObjectLog) LogNotice(msg string, args ...interface{}) {
+	this.log(OBJECT_LOG_LEVEL_NOTICE, nil, msg, args)
 }
 
 // LogWarn writes the log message in WARN level
 func (this *ObjectLog) LogWarn(msg string, args ...interface{}) {
-	this.logger.Warn(this.build(OBJECT_LOG_LEVEL_WARN, msg, args...))
+	this.log(OBJECT_LOG_LEVEL_WARN, nil, msg, args)
 }
 
 // LogError writes the log message in ERROR level
 func (this *ObjectLog) LogError(msg string, args ...interface{}) {
-	this.logger.Error(this.build(OBJECT_LOG_LEVEL_ERROR, msg, args...))
+	this.log(OBJECT_LOG_LEVEL_ERROR, nil, msg, args)
+}
+
+// LogPanic writes the log message in PANIC level and then panics with the message
+func (this *ObjectLog) LogPanic(msg string, args ...interface{}) {
+	this.log(OBJECT_LOG_LEVEL_PANIC, nil, msg, args)
 }
 
-// LogFatal writes the log message in FATAL level - and usually exits (depends on used `ObjectLogger`)
+// LogFatal writes the log message in FATAL level - and usually exits (depends on used `ObjectLogger`). FATAL
+// messages are written regardless of the log level.
 func (this *ObjectLog) LogFatal(msg string, args ...interface{}) {
-	this.logger.Fatal(this.build(OBJECT_LOG_LEVEL_FATAL, msg, args...))
+	this.log(OBJECT_LOG_LEVEL_FATAL, nil, msg, args)
 }
@@ -0,0 +1,85 @@
+package objectlog
+
+import "sync/atomic"
+
+/*
+------------------------------------
+  CHILDREN
+------------------------------------
+*/
+
+// SetLogName sets the name of the object, which is used in the path of children. See `LogChild` and `LogPath`.
+func (this *ObjectLog) SetLogName(name string) *ObjectLog {
+	this.name = name
+	this.override = &atomic.Value{}
+	return this
+}
+
+// LogName returns the name of the object (can be empty string)
+func (this *ObjectLog) LogName() string {
+	return this.name
+}
+
+// SetLogChildSeparator sets the separator, which is appended to the name of a child when composing
+// it's prefix. Children inherit the separator.
+//	obj.SetLogChildSeparator(" > ")
+func (this *ObjectLog) SetLogChildSeparator(separator string) *ObjectLog {
+	this.separator = separator
+	return this
+}
+
+// LogChildSeparator returns the current child separator
+func (this *ObjectLog) LogChildSeparator() string {
+	return this.separator
+}
+
+// LogParent returns the parent, from which the object has been derived with `LogChild` (can be nil)
+func (this *ObjectLog) LogParent() *ObjectLog {
+	return this.parent
+}
+
+// LogPath returns the names of all parents and the name of the object itself, starting with the top
+// most parent. Empty names are skipped.
+//	brand := objectlog.NewObjectLog().SetLogName("DeLorean")
+//	car := brand.LogChild("DMC-12", nil)
+//	car.LogPath() // []string{"DeLorean", "DMC-12"}
+func (this *ObjectLog) LogPath() []string {
+	path := []string{}
+	if this.parent != nil {
+		path = this.parent.LogPath()
+	}
+	if this.name != "" {
+		path = append(path, this.name)
+	}
+	return path
+}
+
+// LogChild derives a child from the object. The child prefix is composed of the prefix of the parent,
+// the name of the child and the child separator. A prefix function of the parent is extended likewise.
+// The child inherits the formatter, suffix and a copy of the args, which are extended by the provided
+// args. The logger and the level of the parent are used for as long as the child does not set own ones,
+// so changes on the parent propagate to the child.
+//	brand := objectlog.NewObjectLog().SetLogPrefix("Brand(DeLorean): ")
+//	car := brand.LogChild("Model(DMC-12)", map[string]interface{}{"model": "DMC-12"})
+//	car.LogInfo("Wrumm") // "Brand(DeLorean): Model(DMC-12): Wrumm :: {"model":"DMC-12"}"
+func (this *ObjectLog) LogChild(name string, args map[string]interface{}) *ObjectLog {
+	child := this.LogCloneObjectLog()
+	child.logger = nil
+	child.level = ""
+	child.name = name
+	child.parent = this
+	child.override = &atomic.Value{}
+	if name != "" {
+		affix := name + this.separator
+		child.prefix = this.prefix + affix
+		if fn := this.prefixFn; fn != nil {
+			child.prefixFn = func(level ObjectLogLevel, logArgs map[string]interface{}) string {
+				return fn(level, logArgs) + affix
+			}
+		}
+	}
+	for k, v := range args {
+		child.args[k] = v
+	}
+	return child
+}
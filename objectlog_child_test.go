@@ -0,0 +1,55 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestObjectLog_LogChild(t *testing.T) {
+	lg := NewBufferObjectLog()
+	parent := NewObjectLog(lg).SetLogName("parent").SetLogPrefix("Parent: ").SetLogArg("foo", "bar")
+	child := parent.LogChild("Child", map[string]interface{}{"baz": "zoing"})
+	grandChild := child.LogChild("GrandChild", nil)
+
+	assert.Equal(t, parent, child.LogParent())
+	assert.Equal(t, "Parent: Child: ", child.LogPrefix())
+	assert.Equal(t, "Parent: Child: GrandChild: ", grandChild.LogPrefix())
+	assert.Equal(t, []string{"parent", "Child", "GrandChild"}, grandChild.LogPath())
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, parent.LogArgs())
+	assert.Equal(t, map[string]interface{}{"foo": "bar", "baz": "zoing"}, grandChild.LogArgs())
+
+	grandChild.LogInfo("Hello")
+	assert.Equal(t, `[INF] Parent: Child: GrandChild: Hello :: {"baz":"zoing","foo":"bar"}`+"\n", lg.Buffer().String())
+}
+
+func TestObjectLog_LogChild_Separator(t *testing.T) {
+	parent := NewObjectLog(NewBufferObjectLog()).SetLogChildSeparator(" > ")
+	child := parent.LogChild("a", nil).LogChild("b", nil)
+	assert.Equal(t, " > ", child.LogChildSeparator())
+	assert.Equal(t, "a > b > ", child.LogPrefix())
+}
+
+func TestObjectLog_LogChild_Propagation(t *testing.T) {
+	lg1 := NewBufferObjectLog()
+	lg2 := NewBufferObjectLog()
+	parent := NewObjectLog(lg1)
+	child := parent.LogChild("Child", nil)
+
+	child.LogInfo("one")
+	parent.SetLogger(lg2).SetLogLevel(OBJECT_LOG_LEVEL_WARN)
+	assert.Equal(t, lg2, child.Logger())
+	assert.Equal(t, OBJECT_LOG_LEVEL_WARN, child.LogLevel())
+	child.LogInfo("two")
+	child.LogWarn("three")
+
+	child.SetLogger(lg1).SetLogLevel(OBJECT_LOG_LEVEL_DEBUG)
+	child.LogDebug("four")
+	parent.LogDebug("five")
+
+	assert.Equal(t, strings.Join([]string{
+		"[INF] Child: one",
+		"[DBG] Child: four",
+	}, "\n")+"\n", lg1.Buffer().String())
+	assert.Equal(t, "[WRN] Child: three\n", lg2.Buffer().String())
+}
@@ -0,0 +1,117 @@
+package objectlog
+
+type (
+
+	// ObjectLogFields adds fields to a single log message, without modifying the args of the `ObjectLog`
+	// it was created from. See `ObjectLog.LogWith`.
+	ObjectLogFields struct {
+		log    *ObjectLog
+		fields map[string]interface{}
+		shared bool
+	}
+)
+
+/*
+------------------------------------
+  FIELDS
+------------------------------------
+*/
+
+// LogWith returns fields, which are added to the log args of the next written message only. The args of
+// the object stay untouched.
+//	obj.LogWith("duration", took).LogInfo("Request finished")
+func (this *ObjectLog) LogWith(key string, value interface{}) *ObjectLogFields {
+	return &ObjectLogFields{
+		log:    this,
+		fields: map[string]interface{}{key: value},
+	}
+}
+
+// LogWithArgs is like `LogWith`, but adds multiple fields at once
+//	obj.LogWithArgs(map[string]interface{}{"status": 200, "duration": took}).LogInfo("Request finished")
+func (this *ObjectLog) LogWithArgs(fields map[string]interface{}) *ObjectLogFields {
+	return (&ObjectLogFields{
+		log:    this,
+		fields: make(map[string]interface{}, len(fields)),
+	}).WithArgs(fields)
+}
+
+// With adds another field. Overwrites existing field with the same name.
+func (this *ObjectLogFields) With(key string, value interface{}) *ObjectLogFields {
+	this.unshare()
+	this.fields[key] = value
+	return this
+}
+
+// WithArgs adds multiple fields. Overwrites existing fields with the same names.
+func (this *ObjectLogFields) WithArgs(fields map[string]interface{}) *ObjectLogFields {
+	this.unshare()
+	for k, v := range fields {
+		this.fields[k] = v
+	}
+	return this
+}
+
+// unshare copies the fields, if they are kept by a written message
+func (this *ObjectLogFields) unshare() {
+	if this.shared {
+		this.fields = copyLogArgs(this.fields, 0)
+		this.shared = false
+	}
+}
+
+// write writes the message with the fields, which are not modified afterwards
+func (this *ObjectLogFields) write(level ObjectLogLevel, msg string, args []interface{}) {
+	this.shared = true
+	this.log.log(level, this.fields, msg, args)
+}
+
+// Fields returns all fields, which must not be modified
+func (this *ObjectLogFields) Fields() map[string]interface{} {
+	return this.fields
+}
+
+// Log writes the log message in the given level
+func (this *ObjectLogFields) Log(level ObjectLogLevel, msg string, args ...interface{}) {
+	this.write(level, msg, args)
+}
+
+// LogTrace writes the log message in TRACE level
+func (this *ObjectLogFields) LogTrace(msg string, args ...interface{}) {
+	this.write(OBJECT_LOG_LEVEL_TRACE, msg, args)
+}
+
+// LogDebug writes the log message in DEBUG level
+func (this *ObjectLogFields) LogDebug(msg string, args ...interface{}) {
+	this.write(OBJECT_LOG_LEVEL_DEBUG, msg, args)
+}
+
+// LogInfo writes the log message in INFO level
+func (this *ObjectLogFields) LogInfo(msg string, args ...interface{}) {
+	this.write(OBJECT_LOG_LEVEL_INFO, msg, args)
+}
+
+// LogNotice writes the log message in NOTICE level
+func (this *ObjectLogFields) LogNotice(msg string, args ...interface{}) {
+	this.write(OBJECT_LOG_LEVEL_NOTICE, msg, args)
+}
+
+// LogWarn writes the log message in WARN level
+func (this *ObjectLogFields) LogWarn(msg string, args ...interface{}) {
+	this.write(OBJECT_LOG_LEVEL_WARN, msg, args)
+}
+
+// LogError writes the log message in ERROR level
+func (this *ObjectLogFields) LogError(msg string, args ...interface{}) {
+	this.write(OBJECT_LOG_LEVEL_ERROR, msg, args)
+}
+
+// LogPanic writes the log message in PANIC level and then panics with the message
+func (this *ObjectLogFields) LogPanic(msg string, args ...interface{}) {
+	this.write(OBJECT_LOG_LEVEL_PANIC, msg, args)
+}
+
+// LogFatal writes the log message in FATAL level - and usually exits (depends on used `ObjectLogger`)
+func (this *ObjectLogFields) LogFatal(msg string, args ...interface{}) {
+	this.write(OBJECT_LOG_LEVEL_FATAL, msg, args)
+}
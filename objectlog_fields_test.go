@@ -0,0 +1,49 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestObjectLog_LogWith(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogArg("foo", "bar")
+	ol.LogWith("baz", "zoing").LogDebug("Hello %s", "foo1")
+	ol.LogWith("foo", "other").With("bla", 1).LogInfo("Hello %s", "foo2")
+	ol.LogWithArgs(map[string]interface{}{"bla": 2}).LogWarn("Hello %s", "foo3")
+	ol.LogWithArgs(nil).LogError("Hello %s", "foo4")
+	ol.LogFatal("Hello %s", "foo5")
+	assert.Equal(t, strings.Join([]string{
+		`[DBG] Hello foo1 :: {"baz":"zoing","foo":"bar"}`,
+		`[INF] Hello foo2 :: {"bla":1,"foo":"other"}`,
+		`[WRN] Hello foo3 :: {"bla":2,"foo":"bar"}`,
+		`[ERR] Hello foo4 :: {"foo":"bar"}`,
+		`[FTL] Hello foo5 :: {"foo":"bar"}`,
+	}, "\n")+"\n", lg.Buffer().String())
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, ol.LogArgs())
+}
+
+func TestObjectLogFields_Level(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogLevel(OBJECT_LOG_LEVEL_ERROR)
+	fields := ol.LogWith("foo", "bar")
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, fields.Fields())
+	fields.LogWarn("Hidden")
+	fields.LogError("Shown")
+	assert.Equal(t, `[ERR] Shown :: {"foo":"bar"}`+"\n", lg.Buffer().String())
+}
+
+func TestObjectLogFields_Shared(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogArg("foo", "bar")
+	fields := ol.LogWith("baz", 1)
+	fields.LogInfo("first")
+	fields.With("baz", 2).LogInfo("second")
+	entries := lg.Entries()
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, map[string]interface{}{"baz": 1}, entries[0].Fields, "written messages keep their fields")
+		assert.Equal(t, map[string]interface{}{"baz": 2}, entries[1].Fields)
+		assert.Equal(t, map[string]interface{}{"foo": "bar"}, entries[1].Args, "fields are not merged into the args")
+	}
+}
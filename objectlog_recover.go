@@ -0,0 +1,53 @@
+package objectlog
+
+import "runtime/debug"
+
+/*
+------------------------------------
+  RECOVER
+------------------------------------
+*/
+
+// SetLogRepanic defines whether recovered panics are re-raised after they have been logged. Defaults to
+// false, which means panics are swallowed.
+//	obj.SetLogRepanic(true)
+func (this *ObjectLog) SetLogRepanic(repanic bool) *ObjectLog {
+	this.repanic = repanic
+	return this
+}
+
+// LogRepanic returns whether recovered panics are re-raised after they have been logged
+func (this *ObjectLog) LogRepanic() bool {
+	return this.repanic
+}
+
+// LogRecover recovers from a panic and writes the panic value and the stack trace in ERROR level, regardless
+// of the log level, so that recovered panics are never dropped. It must be called directly with `defer`,
+// otherwise it cannot recover:
+//	func (this *Foo) Bar() {
+//		defer this.LogRecover()
+//		// ..
+//	}
+func (this *ObjectLog) LogRecover() {
+	if r := recover(); r != nil {
+		writeEntry(this.Logger(), this.build(OBJECT_LOG_LEVEL_ERROR, nil, "Recovered from panic: %v\n%s", r, debug.Stack()))
+		if this.repanic {
+			panic(r)
+		}
+	}
+}
+
+// Go runs the provided function in a new goroutine, which recovers from panics using `LogRecover`. The
+// returned channel is closed when the function has returned.
+//	obj.Go(func() {
+//		// ..
+//	})
+func (this *ObjectLog) Go(fn func()) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer this.LogRecover()
+		fn()
+	}()
+	return done
+}
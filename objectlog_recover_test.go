@@ -0,0 +1,61 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestObjectLog_LogRecover(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogArg("foo", "bar")
+	assert.False(t, ol.LogRepanic())
+	assert.NotPanics(t, func() {
+		defer ol.LogRecover()
+		panic("boom")
+	})
+	out := lg.Buffer().String()
+	assert.True(t, strings.HasPrefix(out, "[ERR] Recovered from panic: boom\n"), out)
+	assert.Contains(t, out, "goroutine")
+	assert.Contains(t, out, ` :: {"foo":"bar"}`)
+}
+
+func TestObjectLog_LogRecover_Repanic(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogRepanic(true)
+	assert.True(t, ol.LogRepanic())
+	assert.True(t, ol.LogCloneObjectLog().LogRepanic())
+	assert.Panics(t, func() {
+		defer ol.LogRecover()
+		panic("boom")
+	})
+	assert.Contains(t, lg.Buffer().String(), "[ERR] Recovered from panic: boom\n")
+}
+
+func TestObjectLog_LogRecover_Level(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogLevel(OBJECT_LOG_LEVEL_FATAL)
+	func() {
+		defer ol.LogRecover()
+		panic("boom")
+	}()
+	assert.Contains(t, lg.Buffer().String(), "[ERR] Recovered from panic: boom\n", "written regardless of the level")
+}
+
+func TestObjectLog_LogRecover_NoPanic(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg)
+	func() {
+		defer ol.LogRecover()
+	}()
+	assert.Equal(t, "", lg.Buffer().String())
+}
+
+func TestObjectLog_Go(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg).SetLogPrefix("PRE ")
+	<-ol.Go(func() {
+		panic("in goroutine")
+	})
+	assert.True(t, strings.HasPrefix(lg.Buffer().String(), "[ERR] PRE Recovered from panic: in goroutine\n"))
+}
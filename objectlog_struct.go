@@ -0,0 +1,176 @@
+package objectlog
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+
+	// structLogArg is a lazy log arg, which reads the current value of a struct field
+	structLogArg struct {
+		root      reflect.Value
+		index     [][]int
+		redact    bool
+		omitEmpty bool
+	}
+
+	// logArgOmitted is returned by lazy values, which should not be logged at all
+	logArgOmitted struct{}
+)
+
+var (
+
+	// RedactedLogValue replaces the values of struct fields tagged with `log:",redact"`
+	RedactedLogValue interface{} = "***"
+
+	objectLogType = reflect.TypeOf(ObjectLog{})
+)
+
+/*
+------------------------------------
+  STRUCT ARGS
+------------------------------------
+*/
+
+// SetLogArgsFromStruct sets log args from all exported fields of the provided struct, which have a `log`
+// tag. The struct must be provided as pointer, so that the current values of the fields are read whenever
+// a message is written. It panics, if anything else is provided.
+//
+// The tag contains the name of the arg, followed by optional comma separated flags. If the name is
+// empty, then the name of the field is used. Supported flags are `omitempty`, which skips zero values,
+// and `redact`, which logs `RedactedLogValue` instead of the actual value. Fields tagged with "-" are
+// ignored. Fields of struct type, which contain tagged fields themselves, are added with their name
+// joined by a dot. Embedded structs without a tag are added without a name.
+//	type Connection struct {
+//		*objectlog.ObjectLog
+//		Peer     string `log:"peer"`
+//		Password string `log:"password,redact"`
+//		Retries  int    `log:",omitempty"`
+//		Backend  struct {
+//			Host string `log:"host"`
+//		} `log:"backend"`
+//	}
+//	conn := &Connection{}
+//	conn.ObjectLog = objectlog.NewObjectLog().SetLogArgsFromStruct(conn)
+//	conn.LogInfo("Hello") // Hello :: {"backend.host":"","password":"***","peer":""}
+func (this *ObjectLog) SetLogArgsFromStruct(obj interface{}) *ObjectLog {
+	root := reflect.ValueOf(obj)
+	if root.Kind() != reflect.Ptr || root.IsNil() || root.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("objectlog: SetLogArgsFromStruct requires pointer to struct, got %T", obj))
+	}
+	args := copyLogArgs(this.args, 0)
+	bindStructLogArgs(args, root, root.Elem().Type(), "", nil, map[reflect.Type]bool{})
+	this.args = args
+	return this
+}
+
+func bindStructLogArgs(args map[string]interface{}, root reflect.Value, typ reflect.Type, prefix string, index [][]int, seen map[reflect.Type]bool) {
+	seen[typ] = true
+	defer delete(seen, typ)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !isExportedStructField(field) {
+			continue
+		}
+		tag, hasTag := field.Tag.Lookup("log")
+		if tag == "-" {
+			continue
+		}
+		fieldIndex := append(append([][]int{}, index...), field.Index)
+		nested := structLogArgType(field.Type)
+		if !hasTag {
+			if field.Anonymous && nested != nil && nested != objectLogType && !seen[nested] {
+				bindStructLogArgs(args, root, nested, prefix, fieldIndex, seen)
+			}
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		if name == "" {
+			name = field.Name
+		}
+		if nested != nil && !seen[nested] && hasStructLogTags(nested, map[reflect.Type]bool{}) {
+			bindStructLogArgs(args, root, nested, prefix+name+".", fieldIndex, seen)
+			continue
+		}
+		arg := &structLogArg{root: root, index: fieldIndex}
+		for _, flag := range parts[1:] {
+			switch flag {
+			case "omitempty":
+				arg.omitEmpty = true
+			case "redact":
+				arg.redact = true
+			}
+		}
+		args[prefix+name] = arg
+	}
+}
+
+// isExportedStructField returns whether the field is exported, or an embedded struct whose exported fields
+// are accessible
+func isExportedStructField(field reflect.StructField) bool {
+	return field.PkgPath == "" || (field.Anonymous && field.Type.Kind() == reflect.Struct)
+}
+
+// structLogArgType returns the struct type of struct or pointer to struct types, otherwise nil
+func structLogArgType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.Struct {
+		return typ
+	}
+	return nil
+}
+
+// hasStructLogTags returns whether the struct type or any embedded struct contains a field with a log tag
+func hasStructLogTags(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	seen[typ] = true
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !isExportedStructField(field) {
+			continue
+		}
+		if _, ok := field.Tag.Lookup("log"); ok {
+			return true
+		}
+		if nested := structLogArgType(field.Type); field.Anonymous && nested != nil && !seen[nested] && hasStructLogTags(nested, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogValue returns the current value of the struct field
+func (this *structLogArg) LogValue() interface{} {
+	value := this.root
+	for _, index := range this.index {
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return logArgOmitted{}
+			}
+			value = value.Elem()
+		}
+		value = value.FieldByIndex(index)
+	}
+	if this.omitEmpty && isEmptyValue(value) {
+		return logArgOmitted{}
+	}
+	if this.redact {
+		return RedactedLogValue
+	}
+	return value.Interface()
+}
+
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	}
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
@@ -0,0 +1,58 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type (
+	testStructLogBackend struct {
+		Host string `log:"host"`
+		Port int    `log:"port,omitempty"`
+	}
+
+	testStructLogEmbedded struct {
+		Region string `log:"region"`
+	}
+
+	testStructLog struct {
+		*ObjectLog
+		testStructLogEmbedded
+		Peer     string `log:"peer"`
+		Password string `log:"password,redact"`
+		Retries  int    `log:",omitempty"`
+		Ignored  string `log:"-"`
+		Untagged string
+		Backend  testStructLogBackend  `log:"backend"`
+		Fallback *testStructLogBackend `log:"fallback"`
+		private  string                `log:"private"`
+	}
+)
+
+func TestObjectLog_SetLogArgsFromStruct(t *testing.T) {
+	lg := NewBufferObjectLog()
+	obj := &testStructLog{Peer: "10.0.0.1", Password: "secret", private: "x"}
+	obj.ObjectLog = NewObjectLog(lg).SetLogArgsFromStruct(obj)
+	obj.Region = "eu"
+	obj.LogInfo("one")
+	obj.Peer = "10.0.0.2"
+	obj.Retries = 3
+	obj.Backend.Port = 8080
+	obj.Fallback = &testStructLogBackend{Host: "backup"}
+	obj.LogInfo("two")
+	assert.Equal(t, strings.Join([]string{
+		`[INF] one :: {"backend.host":"","password":"***","peer":"10.0.0.1","region":"eu"}`,
+		`[INF] two :: {"Retries":3,"backend.host":"","backend.port":8080,"fallback.host":"backup","password":"***","peer":"10.0.0.2","region":"eu"}`,
+	}, "\n")+"\n", lg.Buffer().String())
+}
+
+func TestObjectLog_SetLogArgsFromStruct_Invalid(t *testing.T) {
+	ol := NewObjectLog(NewBufferObjectLog())
+	assert.Panics(t, func() {
+		ol.SetLogArgsFromStruct(testStructLog{})
+	})
+	assert.Panics(t, func() {
+		ol.SetLogArgsFromStruct((*testStructLog)(nil))
+	})
+}
@@ -1,6 +1,7 @@
 package objectlog
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"strings"
 	"testing"
@@ -55,6 +56,21 @@ func TestObjectLog_Args(t *testing.T) {
 		`[FTL] Hello foo5 :: {"foo":"bar"}`,
 	}, "\n")+"\n", lg.Buffer().String())
 }
+func TestObjectLog_ArgsShared(t *testing.T) {
+	lg := NewBufferObjectLog()
+	args := map[string]interface{}{"foo": "bar"}
+	ol := NewObjectLog(lg).SetLogArgs(args)
+	args["baz"] = "copied"
+	ol.LogInfo("Hello")
+	ol.SetLogArg("foo", "changed")
+	ol.LogInfo("Hello")
+	entries := lg.Entries()
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, map[string]interface{}{"foo": "bar"}, entries[0].Args, "written messages keep their args")
+		assert.Equal(t, map[string]interface{}{"foo": "changed"}, entries[1].Args)
+	}
+}
+
 func TestObjectLog_BothIx(t *testing.T) {
 	lg := NewBufferObjectLog()
 	ol := NewObjectLog(lg)
@@ -110,4 +126,55 @@ func TestObjectLog_Clone(t *testing.T) {
 		"foo": "bar",
 		"baz": "zoing",
 	}, from.LogArgs())
-}
\ No newline at end of file
+}
+
+func TestObjectLog_Level(t *testing.T) {
+	lg := NewBufferObjectLog()
+	ol := NewObjectLog(lg)
+	assert.Equal(t, ObjectLogLevel(""), ol.LogLevel())
+	assert.True(t, ol.LogEnabled(OBJECT_LOG_LEVEL_DEBUG))
+	ol.SetLogLevel(OBJECT_LOG_LEVEL_WARN)
+	assert.False(t, ol.LogEnabled(OBJECT_LOG_LEVEL_INFO))
+	assert.True(t, ol.LogEnabled(OBJECT_LOG_LEVEL_ERROR))
+	ol.LogDebug("Hello %s", "foo1")
+	ol.LogInfo("Hello %s", "foo2")
+	ol.LogWarn("Hello %s", "foo3")
+	ol.LogError("Hello %s", "foo4")
+	ol.LogFatal("Hello %s", "foo5")
+	assert.Equal(t, strings.Join([]string{
+		"[WRN] Hello foo3",
+		"[ERR] Hello foo4",
+		"[FTL] Hello foo5",
+	}, "\n")+"\n", lg.Buffer().String())
+}
+
+func TestObjectLog_AffixFuncs(t *testing.T) {
+	lg := NewBufferObjectLog()
+	peer := "unknown"
+	ol := NewObjectLog(lg).
+		SetLogPrefix("STATIC ").
+		SetLogPrefixFunc(func(level ObjectLogLevel, logArgs map[string]interface{}) string {
+			return "(" + peer + ") "
+		}).
+		SetLogSuffixFunc(func(level ObjectLogLevel, logArgs map[string]interface{}) string {
+			return fmt.Sprintf(" [%s, %v]", level, logArgs["foo"])
+		}).
+		SetLogArg("foo", "bar")
+	assert.Equal(t, "STATIC ", ol.LogPrefix())
+	assert.NotNil(t, ol.LogPrefixFunc())
+	assert.NotNil(t, ol.LogSuffixFunc())
+	ol.LogInfo("one")
+	peer = "10.0.0.1"
+	ol.LogWarn("two")
+	clone := ol.LogCloneObjectLog()
+	clone.LogError("three")
+	clone.LogChild("child", nil).LogDebug("four")
+	clone.SetLogPrefixFunc(nil).LogDebug("five")
+	assert.Equal(t, strings.Join([]string{
+		`[INF] (unknown) one [info, bar] :: {"foo":"bar"}`,
+		`[WRN] (10.0.0.1) two [warn, bar] :: {"foo":"bar"}`,
+		`[ERR] (10.0.0.1) three [error, bar] :: {"foo":"bar"}`,
+		`[DBG] (10.0.0.1) child: four [debug, bar] :: {"foo":"bar"}`,
+		`[DBG] STATIC five [debug, bar] :: {"foo":"bar"}`,
+	}, "\n")+"\n", lg.Buffer().String())
+}
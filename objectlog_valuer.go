@@ -0,0 +1,74 @@
+package objectlog
+
+import "fmt"
+
+type (
+
+	// LogValuer can be implemented by log arg values, which should be computed at the time a log message
+	// is written, instead of the time the arg is set
+	LogValuer interface {
+		// LogValue returns the value to be logged
+		LogValue() interface{}
+	}
+
+	// LogValuerFunc implements `LogValuer` for a function
+	LogValuerFunc func() interface{}
+)
+
+// LogValue returns the result of the function
+func (this LogValuerFunc) LogValue() interface{} {
+	return this()
+}
+
+// resolveLogArgs returns the args with all lazy values evaluated. The provided map is returned as is, if
+// it does not contain any lazy value.
+func resolveLogArgs(args map[string]interface{}) map[string]interface{} {
+	var resolved map[string]interface{}
+	for k, v := range args {
+		if !isLazyLogValue(v) {
+			continue
+		}
+		if resolved == nil {
+			resolved = make(map[string]interface{}, len(args))
+			for k2, v2 := range args {
+				resolved[k2] = v2
+			}
+		}
+		value := resolveLogValue(v)
+		if _, omit := value.(logArgOmitted); omit {
+			delete(resolved, k)
+		} else {
+			resolved[k] = value
+		}
+	}
+	if resolved == nil {
+		return args
+	}
+	return resolved
+}
+
+func isLazyLogValue(value interface{}) bool {
+	switch value.(type) {
+	case LogValuer, func() interface{}, func() string:
+		return true
+	}
+	return false
+}
+
+// resolveLogValue evaluates a lazy value. Panics during evaluation are recovered and rendered as value.
+func resolveLogValue(value interface{}) (result interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = fmt.Sprintf("!PANIC(%v)", r)
+		}
+	}()
+	switch v := value.(type) {
+	case LogValuer:
+		return v.LogValue()
+	case func() interface{}:
+		return v()
+	case func() string:
+		return v()
+	}
+	return value
+}
@@ -0,0 +1,50 @@
+package objectlog
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type testLogValuer struct {
+	calls int
+}
+
+func (this *testLogValuer) LogValue() interface{} {
+	this.calls++
+	return this.calls
+}
+
+func TestObjectLog_LazyArgs(t *testing.T) {
+	lg := NewBufferObjectLog()
+	valuer := &testLogValuer{}
+	state := "init"
+	ol := NewObjectLog(lg).
+		SetLogArg("calls", valuer).
+		SetLogArg("state", func() string { return state }).
+		SetLogArg("len", LogValuerFunc(func() interface{} { return len(state) }))
+	ol.LogInfo("one")
+	state = "running"
+	ol.LogWith("fail", func() interface{} { panic("oops") }).LogInfo("two")
+	assert.Equal(t, strings.Join([]string{
+		`[INF] one :: {"calls":1,"len":4,"state":"init"}`,
+		`[INF] two :: {"calls":2,"fail":"!PANIC(oops)","len":7,"state":"running"}`,
+	}, "\n")+"\n", lg.Buffer().String())
+	assert.Equal(t, valuer, ol.LogArgs()["calls"])
+}
+
+func TestObjectLog_LazyArgs_Filtered(t *testing.T) {
+	lg := NewBufferObjectLog()
+	valuer := &testLogValuer{}
+	ol := NewObjectLog(lg).SetLogLevel(OBJECT_LOG_LEVEL_INFO).SetLogArg("calls", valuer)
+	ol.LogDebug("hidden")
+	assert.Equal(t, 0, valuer.calls)
+	ol.LogInfo("shown")
+	assert.Equal(t, 1, valuer.calls)
+}
+
+func TestResolveLogArgs(t *testing.T) {
+	args := map[string]interface{}{"foo": "bar"}
+	assert.Equal(t, args, resolveLogArgs(args))
+	assert.Nil(t, resolveLogArgs(nil))
+}
@@ -0,0 +1,95 @@
+package objectlogtest
+
+import (
+	"fmt"
+	"github.com/ukautz/objectlog"
+	"regexp"
+	"strings"
+)
+
+type (
+
+	// Recorder provides the recorded entries, like `*TestLogger` and `*objectlog.BufferObjectLogger`
+	Recorder interface {
+		Entries() []*objectlog.ObjectLogEntry
+	}
+)
+
+// AssertLogged asserts that an entry of exactly the level, or of any level if empty, was logged, which
+// matches the regular expression pattern. The pattern is matched against the formatted entry, including
+// prefix, suffix and args. It returns whether the assertion was successful.
+//
+//	objectlogtest.AssertLogged(t, lg, objectlog.OBJECT_LOG_LEVEL_WARN, `retry \d+ of 3`)
+func AssertLogged(t TestingT, lg Recorder, level objectlog.ObjectLogLevel, pattern string, msgAndArgs ...interface{}) bool {
+	t.Helper()
+	matches, entries, ok := matchEntries(t, lg, level, pattern)
+	if !ok {
+		return false
+	} else if len(matches) == 0 {
+		t.Errorf("%sexpected %s message matching %q, logged are:%s", assertMessage(msgAndArgs), levelText(level), pattern, entriesText(entries))
+		return false
+	}
+	return true
+}
+
+// AssertNotLogged asserts that no entry of exactly the level, or of any level if empty, was logged, which
+// matches the regular expression pattern. See `AssertLogged`.
+//
+//	objectlogtest.AssertNotLogged(t, lg, objectlog.OBJECT_LOG_LEVEL_ERROR, "")
+func AssertNotLogged(t TestingT, lg Recorder, level objectlog.ObjectLogLevel, pattern string, msgAndArgs ...interface{}) bool {
+	t.Helper()
+	matches, _, ok := matchEntries(t, lg, level, pattern)
+	if !ok {
+		return false
+	} else if len(matches) > 0 {
+		t.Errorf("%sunexpected %s message matching %q, logged are:%s", assertMessage(msgAndArgs), levelText(level), pattern, entriesText(matches))
+		return false
+	}
+	return true
+}
+
+// matchEntries returns the entries of the level matching the pattern and all entries. It fails the test,
+// if the pattern is invalid.
+func matchEntries(t TestingT, lg Recorder, level objectlog.ObjectLogLevel, pattern string) (matches, entries []*objectlog.ObjectLogEntry, ok bool) {
+	t.Helper()
+	expr, err := regexp.Compile(pattern)
+	if err != nil {
+		t.Errorf("invalid pattern %q: %s", pattern, err)
+		return nil, nil, false
+	}
+	entries = lg.Entries()
+	for _, entry := range entries {
+		if (level == "" || entry.Level == level) && expr.MatchString(entry.String()) {
+			matches = append(matches, entry)
+		}
+	}
+	return matches, entries, true
+}
+
+// assertMessage formats the optional message of an assertion, like testify
+func assertMessage(msgAndArgs []interface{}) string {
+	if len(msgAndArgs) == 0 {
+		return ""
+	} else if format, ok := msgAndArgs[0].(string); ok && len(msgAndArgs) > 1 {
+		return fmt.Sprintf(format, msgAndArgs[1:]...) + ": "
+	}
+	return fmt.Sprint(msgAndArgs...) + ": "
+}
+
+func levelText(level objectlog.ObjectLogLevel) string {
+	if level == "" {
+		return "any"
+	}
+	return objectlog.LevelName(level)
+}
+
+func entriesText(entries []*objectlog.ObjectLogEntry) string {
+	if len(entries) == 0 {
+		return " none"
+	}
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = "\n\t[" + objectlog.LevelName(entry.Level) + "] " + entry.String()
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,37 @@
+package objectlogtest
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/ukautz/objectlog"
+	"testing"
+)
+
+func TestAssertLogged(t *testing.T) {
+	lg := objectlog.NewBufferObjectLog()
+	ol := objectlog.NewObjectLog(lg).SetLogArg("attempt", 2)
+	ol.LogWarn("retry %d of 3", 2)
+	ol.LogInfo("done")
+
+	tt := &testT{}
+	assert.True(t, AssertLogged(tt, lg, objectlog.OBJECT_LOG_LEVEL_WARN, `retry \d of 3`))
+	assert.True(t, AssertLogged(tt, lg, "", `"attempt":2`))
+	assert.True(t, AssertNotLogged(tt, lg, objectlog.OBJECT_LOG_LEVEL_ERROR, ""))
+	assert.True(t, AssertNotLogged(tt, lg, objectlog.OBJECT_LOG_LEVEL_INFO, "retry"))
+	assert.Empty(t, tt.errors)
+
+	assert.False(t, AssertLogged(tt, lg, objectlog.OBJECT_LOG_LEVEL_ERROR, "retry", "after %d attempts", 2))
+	assert.False(t, AssertNotLogged(tt, lg, "", "^done"))
+	assert.False(t, AssertLogged(tt, lg, "", "("))
+	assert.Equal(t, []string{
+		"after 2 attempts: expected ERROR message matching \"retry\", logged are:" +
+			"\n\t[WARN] retry 2 of 3 :: {\"attempt\":2}" +
+			"\n\t[INFO] done :: {\"attempt\":2}",
+		"unexpected any message matching \"^done\", logged are:" +
+			"\n\t[INFO] done :: {\"attempt\":2}",
+		"invalid pattern \"(\": error parsing regexp: missing closing ): `(`",
+	}, tt.errors)
+
+	tt = &testT{}
+	assert.False(t, AssertLogged(tt, objectlog.NewBufferObjectLog(), "", "x"))
+	assert.Equal(t, []string{`expected any message matching "x", logged are: none`}, tt.errors)
+}
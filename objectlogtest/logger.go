@@ -0,0 +1,127 @@
+/*
+Package objectlogtest provides a logger writing to the log of a test, and assertions on logged messages.
+
+	func TestSomething(t *testing.T) {
+		lg := objectlogtest.NewTestLogger(t).FailOn(objectlog.OBJECT_LOG_LEVEL_ERROR)
+		thing := NewThing(objectlog.NewObjectLog(lg))
+		thing.Do()
+		objectlogtest.AssertLogged(t, lg, objectlog.OBJECT_LOG_LEVEL_INFO, `^done after \d+ms`)
+		objectlogtest.AssertNotLogged(t, lg, objectlog.OBJECT_LOG_LEVEL_WARN, "")
+	}
+*/
+package objectlogtest
+
+import (
+	"github.com/ukautz/objectlog"
+	"sync"
+)
+
+type (
+
+	// TestingT is the subset of `*testing.T` and `*testing.B`, which is used by the logger and assertions
+	TestingT interface {
+		Helper()
+		Logf(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+
+	// TestLogger writes all messages with `t.Logf`, so that they are shown next to the test which wrote
+	// them, and only if the test fails or runs verbose. All messages are recorded like by
+	// `*objectlog.BufferObjectLogger`, which can be queried. Messages written by goroutines after the test
+	// completed are only recorded, if the test supports `Cleanup` like `*testing.T`.
+	TestLogger struct {
+		*objectlog.BufferObjectLogger
+		t         TestingT
+		failLevel objectlog.ObjectLogLevel
+		mutex     sync.Mutex
+		completed bool
+	}
+)
+
+// NewTestLogger creates new *TestLogger writing to the log of the test
+func NewTestLogger(t TestingT) *TestLogger {
+	this := &TestLogger{
+		BufferObjectLogger: objectlog.NewBufferObjectLog(),
+		t:                  t,
+	}
+	if cleaner, ok := t.(interface{ Cleanup(func()) }); ok {
+		cleaner.Cleanup(this.complete)
+	}
+	return this
+}
+
+// FailOn sets the minimum level of messages, which are unexpected and mark the test as failed. An empty
+// level, the default, disables failing.
+func (this *TestLogger) FailOn(level objectlog.ObjectLogLevel) *TestLogger {
+	this.failLevel = level
+	return this
+}
+
+// LogEntry writes the entry to the log of the test and records it. The test is marked as failed, if the
+// level of the entry is at least the level set with `FailOn`.
+func (this *TestLogger) LogEntry(entry *objectlog.ObjectLogEntry) {
+	this.t.Helper()
+	this.BufferObjectLogger.LogEntry(entry)
+	line := "[" + objectlog.LevelName(entry.Level) + "] " + entry.String()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	if this.completed {
+		return
+	} else if this.failLevel != "" && entry.Level.Enabled(this.failLevel) {
+		this.t.Errorf("unexpected log message: %s", line)
+	} else {
+		this.t.Logf("%s", line)
+	}
+}
+
+// complete stops writing to the test, which must not be written to after it completed
+func (this *TestLogger) complete() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.completed = true
+}
+
+// Trace writes a TRACE message, see `LogEntry`
+func (this *TestLogger) Trace(msg string) {
+	this.LogEntry(objectlog.NewTextEntry(objectlog.OBJECT_LOG_LEVEL_TRACE, msg))
+}
+
+// Debug writes a DEBUG message, see `LogEntry`
+func (this *TestLogger) Debug(msg string) {
+	this.LogEntry(objectlog.NewTextEntry(objectlog.OBJECT_LOG_LEVEL_DEBUG, msg))
+}
+
+// Info writes an INFO message, see `LogEntry`
+func (this *TestLogger) Info(msg string) {
+	this.LogEntry(objectlog.NewTextEntry(objectlog.OBJECT_LOG_LEVEL_INFO, msg))
+}
+
+// Notice writes a NOTICE message, see `LogEntry`
+func (this *TestLogger) Notice(msg string) {
+	this.LogEntry(objectlog.NewTextEntry(objectlog.OBJECT_LOG_LEVEL_NOTICE, msg))
+}
+
+// Warn writes a WARN message, see `LogEntry`
+func (this *TestLogger) Warn(msg string) {
+	this.LogEntry(objectlog.NewTextEntry(objectlog.OBJECT_LOG_LEVEL_WARN, msg))
+}
+
+// Error writes an ERROR message, see `LogEntry`
+func (this *TestLogger) Error(msg string) {
+	this.LogEntry(objectlog.NewTextEntry(objectlog.OBJECT_LOG_LEVEL_ERROR, msg))
+}
+
+// Panic writes a PANIC message, see `LogEntry`
+func (this *TestLogger) Panic(msg string) {
+	this.LogEntry(objectlog.NewTextEntry(objectlog.OBJECT_LOG_LEVEL_PANIC, msg))
+}
+
+// Fatal writes a FATAL message, see `LogEntry`. It DOES NOT EXIT (no call to os.exit)
+func (this *TestLogger) Fatal(msg string) {
+	this.LogEntry(objectlog.NewTextEntry(objectlog.OBJECT_LOG_LEVEL_FATAL, msg))
+}
+
+// Log writes a message of any other level, see `LogEntry`
+func (this *TestLogger) Log(level objectlog.ObjectLogLevel, msg string) {
+	this.LogEntry(objectlog.NewTextEntry(level, msg))
+}
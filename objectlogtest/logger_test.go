@@ -0,0 +1,81 @@
+package objectlogtest
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/ukautz/objectlog"
+	"testing"
+)
+
+// testT records logs and errors instead of writing them to a test
+type testT struct {
+	logs     []string
+	errors   []string
+	cleanups []func()
+}
+
+func (this *testT) Helper() {}
+
+func (this *testT) Logf(format string, args ...interface{}) {
+	this.logs = append(this.logs, fmt.Sprintf(format, args...))
+}
+
+func (this *testT) Errorf(format string, args ...interface{}) {
+	this.errors = append(this.errors, fmt.Sprintf(format, args...))
+}
+
+func (this *testT) Cleanup(fn func()) {
+	this.cleanups = append(this.cleanups, fn)
+}
+
+func TestTestLogger(t *testing.T) {
+	tt := &testT{}
+	lg := NewTestLogger(tt)
+	ol := objectlog.NewObjectLog(lg).SetLogPrefix("pre ").SetLogArg("foo", "bar")
+	ol.LogInfo("hello %s", "world")
+	ol.LogError("failed")
+	lg.Log(objectlog.OBJECT_LOG_LEVEL_NOTICE, "direct")
+	assert.Equal(t, []string{
+		`[INFO] pre hello world :: {"foo":"bar"}`,
+		`[ERROR] pre failed :: {"foo":"bar"}`,
+		`[NOTICE] direct`,
+	}, tt.logs)
+	assert.Empty(t, tt.errors)
+	assert.Equal(t, 1, lg.Count(objectlog.OBJECT_LOG_LEVEL_ERROR))
+	assert.Equal(t, "[INF] pre hello world :: {\"foo\":\"bar\"}\n[ERR] pre failed :: {\"foo\":\"bar\"}\n[NTC] direct\n", lg.Buffer().String())
+}
+
+func TestTestLogger_FailOn(t *testing.T) {
+	tt := &testT{}
+	lg := NewTestLogger(tt).FailOn(objectlog.OBJECT_LOG_LEVEL_ERROR)
+	ol := objectlog.NewObjectLog(lg)
+	ol.LogWarn("expected")
+	ol.LogError("failed")
+	lg.Fatal("fatal")
+	assert.Equal(t, []string{"[WARN] expected"}, tt.logs)
+	assert.Equal(t, []string{
+		"unexpected log message: [ERROR] failed",
+		"unexpected log message: [FATAL] fatal",
+	}, tt.errors)
+}
+
+func TestTestLogger_Completed(t *testing.T) {
+	tt := &testT{}
+	lg := NewTestLogger(tt).FailOn(objectlog.OBJECT_LOG_LEVEL_ERROR)
+	ol := objectlog.NewObjectLog(lg)
+	ol.LogInfo("during")
+	for _, fn := range tt.cleanups {
+		fn()
+	}
+	ol.LogInfo("after")
+	ol.LogError("failed after")
+	assert.Equal(t, []string{"[INFO] during"}, tt.logs)
+	assert.Empty(t, tt.errors)
+	assert.Equal(t, 2, lg.Count(objectlog.OBJECT_LOG_LEVEL_INFO), "still recorded")
+}
+
+func TestTestLogger_T(t *testing.T) {
+	lg := NewTestLogger(t).FailOn(objectlog.OBJECT_LOG_LEVEL_ERROR)
+	objectlog.NewObjectLog(lg).LogInfo("written to the test log")
+	AssertLogged(t, lg, objectlog.OBJECT_LOG_LEVEL_INFO, "test log$")
+}
@@ -0,0 +1,77 @@
+/*
+Package yamlconfig reads the configuration of the logging pipeline from YAML. The document has the same
+structure as the JSON configuration, see `objectlog.Config`, and is validated the same way, so that unknown
+fields are rejected as well.
+
+	level: info
+	sinks:
+	  - type: stderr
+	    formatter: default
+	  - type: file
+	    level: warn
+	    options:
+	      path: /var/log/app.log
+
+The configuration is read like the JSON configuration:
+
+	fh, _ := os.Open("logging.yaml")
+	logger, err := yamlconfig.FromConfig(fh)
+*/
+package yamlconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ukautz/objectlog"
+	"gopkg.in/yaml.v2"
+	"io"
+	"io/ioutil"
+)
+
+// FromConfig reads the configuration as YAML, see `ParseConfig`, builds the logging pipeline and applies it,
+// see `objectlog.Config.Apply`.
+func FromConfig(reader io.Reader) (objectlog.ObjectLogger, error) {
+	config, err := ParseConfig(reader)
+	if err != nil {
+		return nil, err
+	}
+	return config.Apply()
+}
+
+// ParseConfig reads the configuration as YAML. The document is converted to JSON and read with
+// `objectlog.ParseConfig`.
+func ParseConfig(reader io.Reader) (*objectlog.Config, error) {
+	raw, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, &objectlog.ConfigError{Err: err}
+	}
+	var document interface{}
+	if err := yaml.Unmarshal(raw, &document); err != nil {
+		return nil, &objectlog.ConfigError{Err: err}
+	}
+	if raw, err = json.Marshal(jsonValue(document)); err != nil {
+		return nil, &objectlog.ConfigError{Err: err}
+	}
+	return objectlog.ParseConfig(bytes.NewReader(raw))
+}
+
+// jsonValue converts the mappings decoded from YAML, which have keys of any type, into maps with string
+// keys, which can be encoded as JSON
+func jsonValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			converted[fmt.Sprint(key)] = jsonValue(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, item := range value {
+			converted[i] = jsonValue(item)
+		}
+		return converted
+	}
+	return value
+}
@@ -0,0 +1,74 @@
+package yamlconfig
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/ukautz/objectlog"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig(strings.NewReader(`
+level: debug
+overrides: noisy=error
+sinks:
+  - type: buffer
+    formatter: json
+  - type: buffer
+    level: warn
+    formatter:
+      type: text
+      options:
+        color: false
+    filters:
+      - message: ^healthcheck
+        exclude: true
+hooks:
+  - type: args
+    options:
+      args:
+        app: myapp
+        1: one
+redact:
+  args: [password]
+`))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "debug", config.Level)
+	assert.Equal(t, "noisy=error", config.Overrides)
+	if assert.Len(t, config.Sinks, 2) {
+		assert.Equal(t, "json", config.Sinks[0].Formatter.Type)
+		assert.Equal(t, "warn", config.Sinks[1].Level)
+		assert.Equal(t, "text", config.Sinks[1].Formatter.Type)
+		assert.Equal(t, false, config.Sinks[1].Formatter.Options["color"])
+		if assert.Len(t, config.Sinks[1].Filters, 1) {
+			assert.Equal(t, "^healthcheck", config.Sinks[1].Filters[0].Message)
+			assert.True(t, config.Sinks[1].Filters[0].Exclude)
+		}
+	}
+	if assert.Len(t, config.Hooks, 1) {
+		assert.Equal(t, map[string]interface{}{"app": "myapp", "1": "one"}, config.Hooks[0].Options["args"])
+	}
+	if assert.NotNil(t, config.Redact) {
+		assert.Equal(t, []string{"password"}, config.Redact.Args)
+	}
+
+	logger, err := config.Build()
+	if assert.Nil(t, err) {
+		objectlog.NewObjectLog(logger).LogInfo("hello")
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	for config, expect := range map[string]string{
+		"sinks: [{type: stderr}]\nfoo: 1": `objectlog: invalid config: json: unknown field "foo"`,
+		"sinks: [":                        `objectlog: invalid config: yaml: line 1: did not find expected node content`,
+		"sinks: []":                       `objectlog: invalid config at sinks: at least one sink required`,
+	} {
+		_, err := FromConfig(strings.NewReader(config))
+		if assert.NotNil(t, err, config) {
+			assert.Equal(t, expect, err.Error(), config)
+		}
+	}
+}